@@ -0,0 +1,193 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/core/responses"
+	"github.com/bmviniciuss/forger/journal"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// DefaultPath is where the admin API is usually mounted
+const DefaultPath = "/__admin"
+
+type config struct {
+	scenarios core.ScenarioStore
+	journal   *journal.Journal
+}
+
+// Option customizes the admin API built by NewHandler
+type Option func(*config)
+
+// WithScenarioStore makes reset also reset the states of the store's scenarios
+func WithScenarioStore(store core.ScenarioStore) Option {
+	return func(c *config) {
+		c.scenarios = store
+	}
+}
+
+// WithJournal exposes the requests recorded in j under /requests
+func WithJournal(j *journal.Journal) Option {
+	return func(c *config) {
+		c.journal = j
+	}
+}
+
+// Route is the JSON representation of a stored route definition
+type Route struct {
+	ID string `json:"id"`
+	loaders.FileRouteDefinition
+}
+
+// NewHandler creates the admin API managing the definitions of routes:
+//
+//	GET    /routes       lists every route definition
+//	POST   /routes       creates a route definition
+//	GET    /routes/{id}  returns a route definition
+//	PUT    /routes/{id}  replaces a route definition
+//	DELETE /routes/{id}  deletes a route definition
+//	POST   /reset        restores the initial route definitions
+//
+// When a journal is provided with WithJournal, recorded requests are exposed with:
+//
+//	GET    /requests         lists every recorded request
+//	DELETE /requests         clears the recorded requests
+//	POST   /requests/find    lists the requests matching a RequestQuery
+//	POST   /requests/count   counts the requests matching a RequestQuery
+//	POST   /requests/verify  checks that exactly RequestVerification.Count requests match
+func NewHandler(routes *loaders.MemoryLoader, opts ...Option) http.Handler {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	h := &handler{routes: routes, cfg: cfg}
+
+	router := chi.NewRouter()
+	router.Get("/routes", h.list)
+	router.Post("/routes", h.create)
+	router.Get("/routes/{id}", h.get)
+	router.Put("/routes/{id}", h.update)
+	router.Delete("/routes/{id}", h.delete)
+	router.Post("/reset", h.reset)
+	if cfg.journal != nil {
+		router.Get("/requests", h.listRequests)
+		router.Delete("/requests", h.resetRequests)
+		router.Post("/requests/find", h.findRequests)
+		router.Post("/requests/count", h.countRequests)
+		router.Post("/requests/verify", h.verifyRequests)
+	}
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, r, responses.NewNotFoundResponse())
+	})
+	return router
+}
+
+type handler struct {
+	routes *loaders.MemoryLoader
+	cfg    *config
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	stored := h.routes.List()
+	routes := make([]Route, len(stored))
+	for i, route := range stored {
+		routes[i] = newRoute(route)
+	}
+	render.JSON(w, r, routes)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	route, err := h.routes.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		renderStoreError(w, r, err)
+		return
+	}
+	render.JSON(w, r, newRoute(route))
+}
+
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	def, err := decodeDefinition(r)
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid route definition", err.Error()))
+		return
+	}
+	route, err := h.routes.Create(*def)
+	if err != nil {
+		renderStoreError(w, r, err)
+		return
+	}
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, newRoute(route))
+}
+
+func (h *handler) update(w http.ResponseWriter, r *http.Request) {
+	def, err := decodeDefinition(r)
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid route definition", err.Error()))
+		return
+	}
+	route, err := h.routes.Update(chi.URLParam(r, "id"), *def)
+	if err != nil {
+		renderStoreError(w, r, err)
+		return
+	}
+	render.JSON(w, r, newRoute(route))
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.routes.Delete(chi.URLParam(r, "id")); err != nil {
+		renderStoreError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) reset(w http.ResponseWriter, r *http.Request) {
+	h.routes.Reset()
+	if err := resetScenarios(r.Context(), h.cfg.scenarios); err != nil {
+		renderError(w, r, responses.NewInternalErrorResponse("Internal Server Error", err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func resetScenarios(ctx context.Context, store core.ScenarioStore) error {
+	if store == nil {
+		return nil
+	}
+	return store.Reset(ctx)
+}
+
+func newRoute(route loaders.StoredRoute) Route {
+	return Route{
+		ID:                  route.ID,
+		FileRouteDefinition: loaders.NewFileRouteDefinition(route.Definition),
+	}
+}
+
+func decodeDefinition(r *http.Request) (*core.RouteDefinition, error) {
+	var route loaders.FileRouteDefinition
+	if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+		return nil, err
+	}
+	return route.Definition()
+}
+
+func renderStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, loaders.ErrRouteNotFound) {
+		renderError(w, r, responses.NewNotFoundResponse())
+		return
+	}
+	renderError(w, r, responses.NewBadRequestResponse("Invalid route definition", err.Error()))
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, res *responses.Response) {
+	render.Status(r, res.StatusCode)
+	render.JSON(w, r, res)
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/core/responses"
+	"github.com/bmviniciuss/forger/journal"
+	"github.com/go-chi/render"
+)
+
+// RequestQuery is the JSON representation of a journal.Query
+type RequestQuery struct {
+	Method   string                `json:"method,omitempty"`
+	Path     string                `json:"path,omitempty"`
+	Route    string                `json:"route,omitempty"`
+	Matchers []loaders.FileMatcher `json:"matchers,omitempty"`
+}
+
+func (q RequestQuery) Query() (journal.Query, error) {
+	query := journal.Query{
+		Method: q.Method,
+		Path:   q.Path,
+		Route:  q.Route,
+	}
+	for _, m := range q.Matchers {
+		matcher, err := m.Matcher()
+		if err != nil {
+			return journal.Query{}, err
+		}
+		query.Matchers = append(query.Matchers, *matcher)
+	}
+	return query, nil
+}
+
+type RequestVerification struct {
+	RequestQuery
+	Count int `json:"count"`
+}
+
+type RequestCount struct {
+	Count int `json:"count"`
+}
+
+func (h *handler) listRequests(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, h.cfg.journal.Entries())
+}
+
+func (h *handler) resetRequests(w http.ResponseWriter, r *http.Request) {
+	h.cfg.journal.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) findRequests(w http.ResponseWriter, r *http.Request) {
+	var req RequestQuery
+	query, ok := decodeQuery(w, r, &req, &req)
+	if !ok {
+		return
+	}
+	entries, err := h.cfg.journal.Find(query)
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid request query", err.Error()))
+		return
+	}
+	render.JSON(w, r, entries)
+}
+
+func (h *handler) countRequests(w http.ResponseWriter, r *http.Request) {
+	var req RequestQuery
+	query, ok := decodeQuery(w, r, &req, &req)
+	if !ok {
+		return
+	}
+	count, err := h.cfg.journal.Count(query)
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid request query", err.Error()))
+		return
+	}
+	render.JSON(w, r, RequestCount{count})
+}
+
+func (h *handler) verifyRequests(w http.ResponseWriter, r *http.Request) {
+	var req RequestVerification
+	query, ok := decodeQuery(w, r, &req, &req.RequestQuery)
+	if !ok {
+		return
+	}
+	err := h.cfg.journal.Verify(query, req.Count)
+	if errors.Is(err, journal.ErrVerificationFailed) {
+		renderError(w, r, responses.NewVerificationFailedResponse(err.Error()))
+		return
+	}
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid request query", err.Error()))
+		return
+	}
+	render.JSON(w, r, RequestCount{req.Count})
+}
+
+// decodeQuery decodes the request body into dst and converts its query,
+// rendering a bad request response when any of them fails
+func decodeQuery(w http.ResponseWriter, r *http.Request, dst interface{}, q *RequestQuery) (journal.Query, bool) {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid request query", err.Error()))
+		return journal.Query{}, false
+	}
+	query, err := q.Query()
+	if err != nil {
+		renderError(w, r, responses.NewBadRequestResponse("Invalid request query", err.Error()))
+		return journal.Query{}, false
+	}
+	return query, true
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+type DelayDistribution string
+
+const (
+	DELAY_DISTRIBUTION_FIXED       DelayDistribution = "FIXED"
+	DELAY_DISTRIBUTION_UNIFORM     DelayDistribution = "UNIFORM"
+	DELAY_DISTRIBUTION_NORMAL      DelayDistribution = "NORMAL"
+	DELAY_DISTRIBUTION_LOG_NORMAL  DelayDistribution = "LOG_NORMAL"
+	DELAY_DISTRIBUTION_PERCENTILES DelayDistribution = "PERCENTILES"
+)
+
+func NewDelayDistribution(d string) (DelayDistribution, error) {
+	switch d {
+	case DELAY_DISTRIBUTION_FIXED.String():
+		return DELAY_DISTRIBUTION_FIXED, nil
+	case DELAY_DISTRIBUTION_UNIFORM.String():
+		return DELAY_DISTRIBUTION_UNIFORM, nil
+	case DELAY_DISTRIBUTION_NORMAL.String():
+		return DELAY_DISTRIBUTION_NORMAL, nil
+	case DELAY_DISTRIBUTION_LOG_NORMAL.String():
+		return DELAY_DISTRIBUTION_LOG_NORMAL, nil
+	case DELAY_DISTRIBUTION_PERCENTILES.String():
+		return DELAY_DISTRIBUTION_PERCENTILES, nil
+	default:
+		return "", ErrInvalidDelayDistribution
+	}
+}
+
+func (dd DelayDistribution) String() string {
+	return string(dd)
+}
+
+// z99 is the standard normal quantile of the 99th percentile
+const z99 = 2.3263478740408408
+
+// DelayProfile describes how response delays are sampled:
+//
+//	FIXED        always Fixed
+//	UNIFORM      uniformly between Min and Max
+//	NORMAL       normally distributed around Mean with StdDev
+//	LOG_NORMAL   log-normally distributed with Median and Sigma, the standard deviation of its logarithm
+//	PERCENTILES  log-normally distributed so that half of the delays are below P50 and 99% below P99
+//
+// Except for UNIFORM, Min and Max, when set, bound the sampled delays.
+type DelayProfile struct {
+	Distribution DelayDistribution
+	Fixed        time.Duration
+	Min          time.Duration
+	Max          time.Duration
+	Mean         time.Duration
+	StdDev       time.Duration
+	Median       time.Duration
+	Sigma        float64
+	P50          time.Duration
+	P99          time.Duration
+}
+
+// Sample returns a delay drawn from the profile, never negative
+func (p DelayProfile) Sample() time.Duration {
+	var d float64
+	switch p.Distribution {
+	case DELAY_DISTRIBUTION_UNIFORM:
+		if p.Max <= p.Min {
+			return p.Min
+		}
+		return p.Min + time.Duration(rand.Int63n(int64(p.Max-p.Min)+1))
+	case DELAY_DISTRIBUTION_NORMAL:
+		d = float64(p.Mean) + rand.NormFloat64()*float64(p.StdDev)
+	case DELAY_DISTRIBUTION_LOG_NORMAL:
+		d = logNormal(float64(p.Median), p.Sigma)
+	case DELAY_DISTRIBUTION_PERCENTILES:
+		sigma := 0.0
+		if p.P50 > 0 && p.P99 > p.P50 {
+			sigma = math.Log(float64(p.P99)/float64(p.P50)) / z99
+		}
+		d = logNormal(float64(p.P50), sigma)
+	default:
+		d = float64(p.Fixed)
+	}
+	return p.bound(time.Duration(d))
+}
+
+func (p DelayProfile) bound(d time.Duration) time.Duration {
+	if d < p.Min {
+		d = p.Min
+	}
+	if p.Max > 0 && d > p.Max {
+		d = p.Max
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func logNormal(median, sigma float64) float64 {
+	if median <= 0 {
+		return 0
+	}
+	return median * math.Exp(sigma*rand.NormFloat64())
+}
+
+// Validate checks that the profile parameters can be sampled
+func (p DelayProfile) Validate() error {
+	if _, err := NewDelayDistribution(p.Distribution.String()); err != nil {
+		return err
+	}
+	if p.Min < 0 || p.Max < 0 || (p.Max > 0 && p.Max < p.Min) {
+		return ErrInvalidDelayProfile
+	}
+	if p.Distribution == DELAY_DISTRIBUTION_PERCENTILES && p.P99 < p.P50 {
+		return ErrInvalidDelayProfile
+	}
+	if p.StdDev < 0 || p.Sigma < 0 {
+		return ErrInvalidDelayProfile
+	}
+	return nil
+}
+
+// Sleep pauses for d or until ctx is done, returning the context error in the latter case
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func samples(p DelayProfile, n int) []time.Duration {
+	delays := make([]time.Duration, n)
+	for i := range delays {
+		delays[i] = p.Sample()
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	return delays
+}
+
+func TestDelayProfile_Sample(t *testing.T) {
+	t.Run("should sample fixed and uniform delays", func(t *testing.T) {
+		assert.Equal(t, time.Second, DelayProfile{Distribution: DELAY_DISTRIBUTION_FIXED, Fixed: time.Second}.Sample())
+		for _, d := range samples(DelayProfile{Distribution: DELAY_DISTRIBUTION_UNIFORM, Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}, 1000) {
+			assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+			assert.LessOrEqual(t, d, 20*time.Millisecond)
+		}
+	})
+
+	t.Run("should bound normal delays", func(t *testing.T) {
+		delays := samples(DelayProfile{Distribution: DELAY_DISTRIBUTION_NORMAL, Mean: 100 * time.Millisecond, StdDev: 100 * time.Millisecond, Max: 250 * time.Millisecond}, 1000)
+		assert.Equal(t, time.Duration(0), delays[0])
+		assert.Equal(t, 250*time.Millisecond, delays[len(delays)-1])
+		assert.InDelta(t, float64(100*time.Millisecond), float64(delays[500]), float64(20*time.Millisecond))
+	})
+
+	t.Run("should honour percentiles", func(t *testing.T) {
+		delays := samples(DelayProfile{Distribution: DELAY_DISTRIBUTION_PERCENTILES, P50: 100 * time.Millisecond, P99: time.Second}, 20000)
+		assert.InDelta(t, float64(100*time.Millisecond), float64(delays[10000]), float64(10*time.Millisecond))
+		assert.InDelta(t, float64(time.Second), float64(delays[19800]), float64(200*time.Millisecond))
+	})
+
+	t.Run("should reject invalid profiles", func(t *testing.T) {
+		assert.ErrorIs(t, DelayProfile{Distribution: "OTHER"}.Validate(), ErrInvalidDelayDistribution)
+		assert.ErrorIs(t, DelayProfile{Distribution: DELAY_DISTRIBUTION_UNIFORM, Min: time.Second, Max: time.Millisecond}.Validate(), ErrInvalidDelayProfile)
+		assert.ErrorIs(t, DelayProfile{Distribution: DELAY_DISTRIBUTION_PERCENTILES, P50: time.Second, P99: time.Millisecond}.Validate(), ErrInvalidDelayProfile)
+	})
+}
+
+func TestSleep(t *testing.T) {
+	t.Run("should stop sleeping when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := Sleep(ctx, time.Minute)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -7,4 +7,18 @@ var (
 	ErrInvalidRouteResponseType       = errors.New("invalid route response type")
 	ErrInvalidRequestBodyKeysAmount   = errors.New("requestBody function only supports one or zero aguments")
 	ErrInvalidRequestBodyArgumentType = errors.New("requestBody argument should be a string")
+	ErrInvalidMatcherSource           = errors.New("invalid matcher source")
+	ErrInvalidMatchOperator           = errors.New("invalid match operator")
+	ErrInvalidResponseMode            = errors.New("invalid response mode")
+	ErrInvalidRoutePath               = errors.New("route path must start with /")
+	ErrInvalidRouteMethod             = errors.New("route method is required")
+	ErrInvalidStatusCode              = errors.New("status code must be between 100 and 999")
+	ErrInvalidUpstream                = errors.New("upstream must be an absolute URL")
+	ErrInvalidDelayDistribution       = errors.New("invalid delay distribution")
+	ErrInvalidDelayProfile            = errors.New("invalid delay profile")
+	ErrInvalidFaultType               = errors.New("invalid fault type")
+	ErrInvalidFault                   = errors.New("fault chances must be between 0 and 100 in total")
+	ErrInvalidThrottle                = errors.New("throttle values must not be negative")
+	ErrInvalidEvent                   = errors.New("SSE responses require events with non negative delays")
+	ErrInvalidWebSocketScript         = errors.New("WEBSOCKET responses require a script with non negative delays")
 )
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"math/rand"
+	"time"
+)
+
+type FaultType string
+
+const (
+	// FAULT_TYPE_CONNECTION_RESET closes the connection with a TCP reset
+	FAULT_TYPE_CONNECTION_RESET FaultType = "CONNECTION_RESET"
+	// FAULT_TYPE_EMPTY_RESPONSE closes the connection without writing anything
+	FAULT_TYPE_EMPTY_RESPONSE FaultType = "EMPTY_RESPONSE"
+	// FAULT_TYPE_MALFORMED_BODY announces the full body length but writes only half of it
+	FAULT_TYPE_MALFORMED_BODY FaultType = "MALFORMED_BODY"
+	// FAULT_TYPE_RANDOM_DATA writes random bytes instead of an HTTP response and closes the connection
+	FAULT_TYPE_RANDOM_DATA FaultType = "RANDOM_DATA"
+	// FAULT_TYPE_SLOW_DRIP writes the body a few bytes at a time
+	FAULT_TYPE_SLOW_DRIP FaultType = "SLOW_DRIP"
+)
+
+func NewFaultType(t string) (FaultType, error) {
+	switch t {
+	case FAULT_TYPE_CONNECTION_RESET.String():
+		return FAULT_TYPE_CONNECTION_RESET, nil
+	case FAULT_TYPE_EMPTY_RESPONSE.String():
+		return FAULT_TYPE_EMPTY_RESPONSE, nil
+	case FAULT_TYPE_MALFORMED_BODY.String():
+		return FAULT_TYPE_MALFORMED_BODY, nil
+	case FAULT_TYPE_RANDOM_DATA.String():
+		return FAULT_TYPE_RANDOM_DATA, nil
+	case FAULT_TYPE_SLOW_DRIP.String():
+		return FAULT_TYPE_SLOW_DRIP, nil
+	default:
+		return "", ErrInvalidFaultType
+	}
+}
+
+func (ft FaultType) String() string {
+	return string(ft)
+}
+
+const (
+	DefaultDripBytes      = 1
+	DefaultDripInterval   = 100 * time.Millisecond
+	DefaultRandomDataSize = 1024
+)
+
+// Fault replaces a normal response with a misbehaving one.
+// Chance is the percentage, from 0 to 100, of responses affected by the fault.
+// DripBytes and DripInterval configure SLOW_DRIP faults.
+type Fault struct {
+	Type         FaultType
+	Chance       float64
+	DripBytes    int
+	DripInterval time.Duration
+}
+
+func (f Fault) Validate() error {
+	if _, err := NewFaultType(f.Type.String()); err != nil {
+		return err
+	}
+	if f.Chance < 0 || f.Chance > 100 || f.DripBytes < 0 || f.DripInterval < 0 {
+		return ErrInvalidFault
+	}
+	return nil
+}
+
+// PickFault rolls the chances of the response faults, returning the one to
+// inject or nil when the response should be served normally
+func (rr RouteResponse) PickFault() *Fault {
+	if len(rr.Faults) == 0 {
+		return nil
+	}
+	roll := rand.Float64() * 100
+	var cumulative float64
+	for i := range rr.Faults {
+		cumulative += rr.Faults[i].Chance
+		if roll < cumulative {
+			return &rr.Faults[i]
+		}
+	}
+	return nil
+}
+
+func validateFaults(faults []Fault) error {
+	var total float64
+	for _, f := range faults {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+		total += f.Chance
+	}
+	if total > 100 {
+		return ErrInvalidFault
+	}
+	return nil
+}
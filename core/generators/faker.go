@@ -0,0 +1,344 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type FakeType string
+
+var (
+	FakeTypeName       FakeType = "name"
+	FakeTypeFirstName  FakeType = "first_name"
+	FakeTypeLastName   FakeType = "last_name"
+	FakeTypeEmail      FakeType = "email"
+	FakeTypePhone      FakeType = "phone"
+	FakeTypeAddress    FakeType = "address"
+	FakeTypeStreet     FakeType = "street"
+	FakeTypeCity       FakeType = "city"
+	FakeTypePostalCode FakeType = "postal_code"
+	FakeTypeCountry    FakeType = "country"
+	FakeTypeCompany    FakeType = "company"
+	FakeTypeIBAN       FakeType = "iban"
+	FakeTypeCreditCard FakeType = "credit_card"
+	FakeTypeWord       FakeType = "word"
+	FakeTypeSentence   FakeType = "sentence"
+	FakeTypeParagraph  FakeType = "paragraph"
+	FakeTypeIPv4       FakeType = "ipv4"
+	FakeTypeIPv6       FakeType = "ipv6"
+)
+
+var (
+	ErrUnknownFakeType = errors.New("unknown fake type")
+	ErrUnknownLocale   = errors.New("unknown locale")
+)
+
+// Faker generates realistic looking values for a locale
+type Faker struct {
+	locale fakerLocale
+	rand   *rand.Rand
+}
+
+// NewFaker returns a faker for the locale, DefaultLocale when empty. Fakers
+// built with the same seed generate the same values in the same order,
+// otherwise they are seeded from the clock.
+func NewFaker(locale string, seed ...int64) (*Faker, error) {
+	s := time.Now().UnixNano()
+	if len(seed) > 0 {
+		s = seed[0]
+	}
+	return newFaker(locale, rand.New(rand.NewSource(s)))
+}
+
+func newFaker(locale string, rnd *rand.Rand) (*Faker, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	l, ok := fakerLocales[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLocale, locale)
+	}
+	return &Faker{locale: l, rand: rnd}, nil
+}
+
+// Fake generates a value of the fake type given as first option, using the
+// locale and seed optionally given as second and third ones.
+// A seeded name and a seeded email share the same person, so
+// {{ fake "name" "en_US" 7 }} and {{ fake "email" "en_US" 7 }} go together.
+// Without a seed values are drawn from the source of the context, see WithSeed.
+func Fake(ctx context.Context, options ...interface{}) (string, error) {
+	// Signature Fake(ctx, type)
+	if len(options) <= 0 {
+		return "", errors.New("missing type for fake function")
+	}
+	tRaw, ok := options[0].(string)
+	if !ok {
+		return "", errors.New("invalid type for fake function")
+	}
+
+	// Signature Fake(ctx, type, locale)
+	locale := ""
+	if len(options) > 1 {
+		if locale, ok = options[1].(string); !ok {
+			return "", errors.New("invalid locale for fake function")
+		}
+	}
+
+	// Signature Fake(ctx, type, locale, seed)
+	rnd := Rand(ctx)
+	if len(options) > 2 {
+		seed, err := ParseSeed(options[2])
+		if err != nil {
+			return "", err
+		}
+		rnd = rand.New(rand.NewSource(seed))
+	}
+	f, err := newFaker(locale, rnd)
+	if err != nil {
+		return "", err
+	}
+	return f.Generate(FakeType(tRaw))
+}
+
+// Generate returns a value of the fake type
+func (f *Faker) Generate(t FakeType) (string, error) {
+	switch t {
+	case FakeTypeName:
+		return f.Name(), nil
+	case FakeTypeFirstName:
+		return f.FirstName(), nil
+	case FakeTypeLastName:
+		return f.LastName(), nil
+	case FakeTypeEmail:
+		return f.Email(), nil
+	case FakeTypePhone:
+		return f.Phone(), nil
+	case FakeTypeAddress:
+		return f.Address(), nil
+	case FakeTypeStreet:
+		return f.Street(), nil
+	case FakeTypeCity:
+		return f.City(), nil
+	case FakeTypePostalCode:
+		return f.PostalCode(), nil
+	case FakeTypeCountry:
+		return f.Country(), nil
+	case FakeTypeCompany:
+		return f.Company(), nil
+	case FakeTypeIBAN:
+		return f.IBAN(), nil
+	case FakeTypeCreditCard:
+		return f.CreditCard(), nil
+	case FakeTypeWord:
+		return f.Word(), nil
+	case FakeTypeSentence:
+		return f.Sentence(), nil
+	case FakeTypeParagraph:
+		return f.Paragraph(), nil
+	case FakeTypeIPv4:
+		return f.IPv4(), nil
+	case FakeTypeIPv6:
+		return f.IPv6(), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFakeType, t)
+	}
+}
+
+func (f *Faker) FirstName() string {
+	return f.pick(f.locale.firstNames)
+}
+
+func (f *Faker) LastName() string {
+	return f.pick(f.locale.lastNames)
+}
+
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+func (f *Faker) Email() string {
+	first, last := f.FirstName(), f.LastName()
+	return emailPart(first) + "." + emailPart(last) + "@" + f.pick(f.locale.emailDomains)
+}
+
+func (f *Faker) Phone() string {
+	return f.format(f.locale.phoneFormat)
+}
+
+func (f *Faker) Street() string {
+	return f.pick(f.locale.streets)
+}
+
+func (f *Faker) City() string {
+	return f.pick(f.locale.cities)
+}
+
+func (f *Faker) PostalCode() string {
+	return f.format(f.locale.postalFormat)
+}
+
+func (f *Faker) Country() string {
+	return f.locale.country
+}
+
+func (f *Faker) Address() string {
+	return strings.NewReplacer(
+		"{street}", f.Street(),
+		"{number}", strconv.Itoa(1+f.rand.Intn(999)),
+		"{city}", f.City(),
+		"{postal}", f.PostalCode(),
+	).Replace(f.locale.addressFormat)
+}
+
+func (f *Faker) Company() string {
+	if f.rand.Intn(3) == 0 {
+		return f.LastName() + " & " + f.LastName()
+	}
+	return f.LastName() + " " + f.pick(f.locale.companySuffix)
+}
+
+// IBAN returns an IBAN of the locale's country with valid check digits.
+// Locales of countries without IBANs use the one of a close country.
+func (f *Faker) IBAN() string {
+	country := f.locale.ibanCountry
+	bban := f.format(f.locale.ibanBBANFormat)
+	return country + ibanCheckDigits(country, bban) + bban
+}
+
+// CreditCard returns a Visa, Mastercard or American Express number with a valid Luhn check digit
+func (f *Faker) CreditCard() string {
+	var prefix string
+	length := 16
+	switch f.rand.Intn(3) {
+	case 0:
+		prefix = "4"
+	case 1:
+		prefix = strconv.Itoa(51 + f.rand.Intn(5))
+	default:
+		prefix = []string{"34", "37"}[f.rand.Intn(2)]
+		length = 15
+	}
+	number := prefix + f.digits(length-len(prefix)-1)
+	return number + luhnCheckDigit(number)
+}
+
+func (f *Faker) Word() string {
+	return f.pick(loremWords)
+}
+
+func (f *Faker) Sentence() string {
+	words := make([]string, 6+f.rand.Intn(7))
+	for i := range words {
+		words[i] = f.Word()
+	}
+	sentence := strings.Join(words, " ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+func (f *Faker) Paragraph() string {
+	sentences := make([]string, 3+f.rand.Intn(3))
+	for i := range sentences {
+		sentences[i] = f.Sentence()
+	}
+	return strings.Join(sentences, " ")
+}
+
+func (f *Faker) IPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", 1+f.rand.Intn(223), f.rand.Intn(256), f.rand.Intn(256), 1+f.rand.Intn(254))
+}
+
+func (f *Faker) IPv6() string {
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = strconv.FormatInt(int64(f.rand.Intn(0x10000)), 16)
+	}
+	return strings.Join(groups, ":")
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.rand.Intn(len(values))]
+}
+
+func (f *Faker) digits(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteByte(byte('0' + f.rand.Intn(10)))
+	}
+	return b.String()
+}
+
+// format replaces each # of the pattern by a digit and each A by an uppercase letter
+func (f *Faker) format(pattern string) string {
+	var b strings.Builder
+	for _, c := range pattern {
+		switch c {
+		case '#':
+			b.WriteByte(byte('0' + f.rand.Intn(10)))
+		case 'A':
+			b.WriteByte(byte('A' + f.rand.Intn(26)))
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// luhnCheckDigit returns the digit making number pass the Luhn check
+func luhnCheckDigit(number string) string {
+	sum := 0
+	double := true
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return strconv.Itoa((10 - sum%10) % 10)
+}
+
+// ibanCheckDigits computes the ISO 13616 mod 97 check digits
+func ibanCheckDigits(country, bban string) string {
+	var digits strings.Builder
+	for _, c := range bban + country + "00" {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return fmt.Sprintf("%02d", check)
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "ae",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "oe",
+	"ú", "u", "ù", "u", "û", "u", "ü", "ue",
+	"ç", "c", "ñ", "n", "ß", "ss",
+)
+
+// emailPart lowercases a name and drops what can not be part of an address
+func emailPart(name string) string {
+	name = accents.Replace(strings.ToLower(name))
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,104 @@
+package generators
+
+// fakerLocale holds the data fake values are drawn from.
+// In formats, # is replaced by a digit and A by an uppercase letter.
+type fakerLocale struct {
+	firstNames     []string
+	lastNames      []string
+	streets        []string
+	cities         []string
+	country        string
+	postalFormat   string
+	phoneFormat    string
+	addressFormat  string
+	companySuffix  []string
+	emailDomains   []string
+	ibanCountry    string
+	ibanBBANFormat string
+}
+
+const DefaultLocale = "en_US"
+
+var fakerLocales = map[string]fakerLocale{
+	"en_US": {
+		firstNames:     []string{"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth", "William", "Susan", "Richard", "Jessica", "Thomas", "Sarah"},
+		lastNames:      []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Wilson", "Anderson", "Taylor", "Moore", "Jackson", "Martin", "Lee", "Thompson"},
+		streets:        []string{"Main St", "Oak Ave", "Maple Dr", "Cedar Ln", "Pine St", "Elm St", "Washington Blvd", "Lake Rd", "Hill St", "Park Ave"},
+		cities:         []string{"Springfield", "Riverside", "Franklin", "Greenville", "Madison", "Austin", "Portland", "Denver", "Boston", "Seattle"},
+		country:        "United States",
+		postalFormat:   "#####",
+		phoneFormat:    "+1 (###) ###-####",
+		addressFormat:  "{number} {street}, {city}, {postal}",
+		companySuffix:  []string{"Inc.", "LLC", "Group", "Corp.", "Holdings"},
+		emailDomains:   []string{"example.com", "mail.com", "inbox.com"},
+		ibanCountry:    "GB",
+		ibanBBANFormat: "AAAA##############",
+	},
+	"pt_BR": {
+		firstNames:     []string{"João", "Maria", "José", "Ana", "Pedro", "Juliana", "Lucas", "Fernanda", "Gabriel", "Camila", "Rafael", "Beatriz", "Mateus", "Larissa", "Gustavo", "Letícia"},
+		lastNames:      []string{"Silva", "Santos", "Oliveira", "Souza", "Rodrigues", "Ferreira", "Alves", "Pereira", "Lima", "Gomes", "Costa", "Ribeiro", "Martins", "Carvalho", "Araújo", "Melo"},
+		streets:        []string{"Rua das Flores", "Avenida Paulista", "Rua XV de Novembro", "Rua da Consolação", "Avenida Brasil", "Rua Augusta", "Rua Sete de Setembro", "Avenida Atlântica"},
+		cities:         []string{"São Paulo", "Rio de Janeiro", "Belo Horizonte", "Curitiba", "Porto Alegre", "Salvador", "Recife", "Fortaleza", "Florianópolis", "Brasília"},
+		country:        "Brasil",
+		postalFormat:   "#####-###",
+		phoneFormat:    "+55 (##) 9####-####",
+		addressFormat:  "{street}, {number} - {city}, {postal}",
+		companySuffix:  []string{"Ltda.", "S.A.", "e Filhos", "Comércio", "Tecnologia"},
+		emailDomains:   []string{"exemplo.com.br", "email.com.br", "correio.com.br"},
+		ibanCountry:    "BR",
+		ibanBBANFormat: "#######################A#",
+	},
+	"de_DE": {
+		firstNames:     []string{"Lukas", "Anna", "Leon", "Lena", "Finn", "Marie", "Jonas", "Sophie", "Paul", "Laura", "Felix", "Julia", "Maximilian", "Hannah", "Elias", "Lea"},
+		lastNames:      []string{"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann", "Koch", "Richter", "Klein", "Wolf", "Schröder", "Neumann"},
+		streets:        []string{"Hauptstraße", "Schulstraße", "Bahnhofstraße", "Gartenstraße", "Dorfstraße", "Bergstraße", "Lindenstraße", "Kirchstraße"},
+		cities:         []string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt am Main", "Stuttgart", "Düsseldorf", "Leipzig", "Dresden", "Bremen"},
+		country:        "Deutschland",
+		postalFormat:   "#####",
+		phoneFormat:    "+49 ### #######",
+		addressFormat:  "{street} {number}, {postal} {city}",
+		companySuffix:  []string{"GmbH", "AG", "KG", "GmbH & Co. KG", "e.V."},
+		emailDomains:   []string{"beispiel.de", "mail.de", "post.de"},
+		ibanCountry:    "DE",
+		ibanBBANFormat: "##################",
+	},
+	"fr_FR": {
+		firstNames:     []string{"Gabriel", "Louise", "Raphaël", "Emma", "Léo", "Jade", "Louis", "Alice", "Lucas", "Chloé", "Hugo", "Lina", "Arthur", "Rose", "Jules", "Léa"},
+		lastNames:      []string{"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau", "Simon", "Laurent", "Lefebvre", "Michel", "Garcia", "Roux"},
+		streets:        []string{"rue de la Paix", "avenue des Champs-Élysées", "rue Victor Hugo", "boulevard Saint-Michel", "rue de la République", "rue Nationale", "place de la Mairie", "rue du Moulin"},
+		cities:         []string{"Paris", "Marseille", "Lyon", "Toulouse", "Nice", "Nantes", "Strasbourg", "Montpellier", "Bordeaux", "Lille"},
+		country:        "France",
+		postalFormat:   "#####",
+		phoneFormat:    "+33 # ## ## ## ##",
+		addressFormat:  "{number} {street}, {postal} {city}",
+		companySuffix:  []string{"SARL", "SA", "SAS", "et Fils", "Groupe"},
+		emailDomains:   []string{"exemple.fr", "courriel.fr", "mail.fr"},
+		ibanCountry:    "FR",
+		ibanBBANFormat: "#######################",
+	},
+	"es_ES": {
+		firstNames:     []string{"Hugo", "Lucía", "Martín", "Sofía", "Pablo", "María", "Mateo", "Martina", "Daniel", "Paula", "Alejandro", "Julia", "Álvaro", "Valeria", "Adrián", "Carmen"},
+		lastNames:      []string{"García", "Fernández", "González", "Rodríguez", "López", "Martínez", "Sánchez", "Pérez", "Gómez", "Martín", "Jiménez", "Ruiz", "Hernández", "Díaz", "Moreno", "Álvarez"},
+		streets:        []string{"Calle Mayor", "Gran Vía", "Calle de Alcalá", "Paseo de la Castellana", "Calle Real", "Avenida de la Constitución", "Calle del Sol", "Plaza de España"},
+		cities:         []string{"Madrid", "Barcelona", "Valencia", "Sevilla", "Zaragoza", "Málaga", "Bilbao", "Granada", "Alicante", "Córdoba"},
+		country:        "España",
+		postalFormat:   "#####",
+		phoneFormat:    "+34 6## ### ###",
+		addressFormat:  "{street}, {number}, {postal} {city}",
+		companySuffix:  []string{"S.L.", "S.A.", "y Asociados", "Hermanos", "Grupo"},
+		emailDomains:   []string{"ejemplo.es", "correo.es", "mail.es"},
+		ibanCountry:    "ES",
+		ibanBBANFormat: "####################",
+	},
+}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+	"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+	"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	"exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo",
+	"consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint",
+	"occaecat", "cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia",
+	"deserunt", "mollit", "anim", "id", "est", "laborum",
+}
@@ -0,0 +1,73 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Number is a numeric template value. Integers are kept as int64 besides
+// their float64 value, which can not hold every integer above 2^53.
+type Number struct {
+	Int   int64
+	Float float64
+	IsInt bool
+}
+
+func IntNumber(i int64) Number {
+	return Number{Int: i, Float: float64(i), IsInt: true}
+}
+
+func FloatNumber(f float64) Number {
+	return Number{Float: f}
+}
+
+// ToNumber converts template values, including the strings returned by
+// requestVar and requestQuery and the raw JSON returned by requestBody
+func ToNumber(v interface{}) (Number, error) {
+	switch n := v.(type) {
+	case int:
+		return IntNumber(int64(n)), nil
+	case int64:
+		return IntNumber(n), nil
+	case float64:
+		return FloatNumber(n), nil
+	case string:
+		s := strings.Trim(strings.TrimSpace(n), `"`)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return IntNumber(i), nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Number{}, fmt.Errorf("%q is not a number", n)
+		}
+		return FloatNumber(f), nil
+	default:
+		return Number{}, fmt.Errorf("%v is not a number", v)
+	}
+}
+
+// Value returns the number as an int64 or a float64
+func (n Number) Value() interface{} {
+	if n.IsInt {
+		return n.Int
+	}
+	return n.Float
+}
+
+// toInt converts template values to integers, accepting numbers without
+// decimals like the 5.0 of a JSON body
+func toInt(v interface{}) (int64, error) {
+	n, err := ToNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	if n.IsInt {
+		return n.Int, nil
+	}
+	if n.Float != math.Trunc(n.Float) || n.Float < math.MinInt64 || n.Float >= math.MaxInt64 {
+		return 0, fmt.Errorf("%v is not an integer", v)
+	}
+	return int64(n.Float), nil
+}
@@ -0,0 +1,89 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type randKey struct{}
+
+// WithSeed returns a context whose generators draw their values from a source
+// seeded with seed, so the same seed always renders the same values in the
+// same order. Values of contexts without a seed are random.
+func WithSeed(ctx context.Context, seed int64) context.Context {
+	source := &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+	return context.WithValue(ctx, randKey{}, rand.New(source))
+}
+
+// random is shared by the contexts without a seed
+var random = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)})
+
+// Rand returns the seeded source of the context, or a shared source seeded
+// from the clock when the context has no seed
+func Rand(ctx context.Context) *rand.Rand {
+	if ctx != nil {
+		if rnd, ok := ctx.Value(randKey{}).(*rand.Rand); ok {
+			return rnd
+		}
+	}
+	return random
+}
+
+// IsSeeded reports whether the generators of the context draw from a seeded source
+func IsSeeded(ctx context.Context) bool {
+	_, ok := ctx.Value(randKey{}).(*rand.Rand)
+	return ok
+}
+
+// ParseSeed converts a template value into a seed. Integers and integer
+// strings are used as they are, any other text is hashed, so request values
+// like ids and emails can be used as seeds.
+func ParseSeed(v interface{}) (int64, error) {
+	switch s := v.(type) {
+	case int:
+		return int64(s), nil
+	case int64:
+		return s, nil
+	case float64:
+		return int64(s), nil
+	case string:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		return int64(h.Sum64()), nil
+	default:
+		return 0, errors.New("invalid seed")
+	}
+}
+
+// lockedSource lets the values of a request be drawn from several goroutines,
+// like the pushes of a WebSocket conversation
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
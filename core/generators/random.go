@@ -0,0 +1,142 @@
+package generators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+var charsets = map[string]string{
+	"alpha":        "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"alphanumeric": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	"numeric":      "0123456789",
+	"lower":        "abcdefghijklmnopqrstuvwxyz",
+	"upper":        "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"hex":          "0123456789abcdef",
+}
+
+const defaultRandomStringLength = 16
+
+// RandomInt generates an integer between the min and max options, both included
+func RandomInt(ctx context.Context, options ...interface{}) (string, error) {
+	// Signature RandomInt(ctx, min, max)
+	if len(options) != 2 {
+		return "", errors.New("randomInt expects a min and a max")
+	}
+	min, err := toInt(options[0])
+	if err != nil {
+		return "", err
+	}
+	max, err := toInt(options[1])
+	if err != nil {
+		return "", err
+	}
+	if max < min {
+		return "", errors.New("randomInt max must not be lower than min")
+	}
+	return strconv.FormatInt(min+int64(uint64n(Rand(ctx), uint64(max-min))), 10), nil
+}
+
+// uint64n returns a value between 0 and max, both included, so the range of
+// randomInt can span every int64
+func uint64n(rnd *rand.Rand, max uint64) uint64 {
+	if max < math.MaxInt64 {
+		return uint64(rnd.Int63n(int64(max) + 1))
+	}
+	if max == math.MaxUint64 {
+		return rnd.Uint64()
+	}
+	n := max + 1
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := rnd.Uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// RandomFloat generates a number between the min and max options, rounded
+// to the number of decimals optionally given as third option, 2 by default
+func RandomFloat(ctx context.Context, options ...interface{}) (string, error) {
+	// Signature RandomFloat(ctx, min, max)
+	if len(options) < 2 {
+		return "", errors.New("randomFloat expects a min and a max")
+	}
+	minNumber, err := ToNumber(options[0])
+	if err != nil {
+		return "", err
+	}
+	maxNumber, err := ToNumber(options[1])
+	if err != nil {
+		return "", err
+	}
+	min, max := minNumber.Float, maxNumber.Float
+	if max < min {
+		return "", errors.New("randomFloat max must not be lower than min")
+	}
+
+	// Signature RandomFloat(ctx, min, max, decimals)
+	decimals := int64(2)
+	if len(options) > 2 {
+		if decimals, err = toInt(options[2]); err != nil {
+			return "", err
+		}
+	}
+	value := min + Rand(ctx).Float64()*(max-min)
+	return strconv.FormatFloat(value, 'f', int(decimals), 64), nil
+}
+
+// RandomChoice picks one of the options
+func RandomChoice(ctx context.Context, options ...interface{}) (string, error) {
+	if len(options) == 0 {
+		return "", errors.New("randomChoice expects at least one value")
+	}
+	return fmt.Sprint(options[Rand(ctx).Intn(len(options))]), nil
+}
+
+// RandomBool generates true or false
+func RandomBool(ctx context.Context, options ...interface{}) (string, error) {
+	return strconv.FormatBool(Rand(ctx).Intn(2) == 1), nil
+}
+
+// RandomString generates a string of the length given as first option, 16 by
+// default, from the charset given as second option. The charset is either
+// one of alpha, alphanumeric (the default), numeric, lower, upper and hex,
+// or the characters to pick from.
+func RandomString(ctx context.Context, options ...interface{}) (string, error) {
+	// Signature RandomString(ctx, length)
+	length := int64(defaultRandomStringLength)
+	if len(options) > 0 {
+		var err error
+		if length, err = toInt(options[0]); err != nil {
+			return "", err
+		}
+		if length < 0 {
+			return "", errors.New("randomString length must not be negative")
+		}
+	}
+
+	// Signature RandomString(ctx, length, charset)
+	charset := charsets["alphanumeric"]
+	if len(options) > 1 {
+		raw, ok := options[1].(string)
+		if !ok || raw == "" {
+			return "", errors.New("invalid charset for randomString function")
+		}
+		charset = raw
+		if named, ok := charsets[raw]; ok {
+			charset = named
+		}
+	}
+	chars := []rune(charset)
+	rnd := Rand(ctx)
+	var b strings.Builder
+	for i := int64(0); i < length; i++ {
+		b.WriteRune(chars[rnd.Intn(len(chars))])
+	}
+	return b.String(), nil
+}
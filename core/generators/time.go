@@ -3,6 +3,11 @@ package generators
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,8 +18,10 @@ const (
 type TimeType string
 
 var (
-	TimeTypeIso8601 TimeType = "iso8601"
-	TimeTypeRfc3339 TimeType = "rfc3339"
+	TimeTypeIso8601   TimeType = "iso8601"
+	TimeTypeRfc3339   TimeType = "rfc3339"
+	TimeTypeUnix      TimeType = "unix"
+	TimeTypeUnixMilli TimeType = "unixmilli"
 )
 
 func (t TimeType) Format() string {
@@ -34,23 +41,397 @@ func NewTimeType(timeType string) TimeType {
 		return TimeTypeIso8601
 	case "rfc3339":
 		return TimeTypeRfc3339
+	case "unix":
+		return TimeTypeUnix
+	case "unixmilli":
+		return TimeTypeUnixMilli
 	default:
 		return TimeTypeIso8601
 	}
 }
 
-// ctx type now
+// Time formats the current time, or the time.Time given among the options,
+// usually parsed from the request with ParseTime. String options are either:
+//   - a format: iso8601 (the default, also used for unknown formats), rfc3339,
+//     unix, unixmilli or a strftime layout like "%Y-%m-%d"
+//   - layout=<Go layout>, like "layout=2006-01-02", formatting with a Go layout
+//   - an offset: "now", "+3d", "now-2h" or "-1d12h", see ParseOffset
+//   - tz=<IANA zone>, the timezone the time is shifted to before formatting,
+//     iso8601 times always being in UTC
+//   - truncate=<unit>, truncating the shifted time to the start of its
+//     second, minute, hour, day, month or year
 func Time(ctx context.Context, options ...interface{}) (string, error) {
 	// Signature Time(ctx)
 	if len(options) <= 0 {
-		return time.Now().Format(utcLayout), nil
+		return time.Now().UTC().Format(utcLayout), nil
 	}
 
-	// Signature UUID(ctx, type)
-	tRaw, ok := options[0].(string)
-	if !ok {
-		return "", errors.New("invalid type for time function")
+	// Signature Time(ctx, options...)
+	t := time.Now()
+	format := ""
+	layout := ""
+	var offset Offset
+	var loc *time.Location
+	truncate := ""
+	for _, option := range options {
+		switch o := option.(type) {
+		case time.Time:
+			t = o
+		case string:
+			key, value, isKeyValue := strings.Cut(o, "=")
+			switch {
+			case isKeyValue && key == "tz":
+				l, err := time.LoadLocation(value)
+				if err != nil {
+					return "", fmt.Errorf("invalid timezone for time function: %w", err)
+				}
+				loc = l
+			case isKeyValue && key == "truncate":
+				truncate = value
+			case isKeyValue && key == "layout":
+				layout = value
+			case isOffset(o):
+				off, err := ParseOffset(o)
+				if err != nil {
+					return "", err
+				}
+				if offset, err = offset.Add(off); err != nil {
+					return "", err
+				}
+			case format == "":
+				format = o
+			default:
+				return "", fmt.Errorf("unexpected option %q for time function", o)
+			}
+		default:
+			return "", errors.New("invalid type for time function")
+		}
 	}
-	t := NewTimeType(tRaw)
-	return time.Now().Format(t.Format()), nil
+	if loc != nil {
+		t = t.In(loc)
+	}
+	t = offset.Apply(t)
+	t, err := truncateTime(t, truncate)
+	if err != nil {
+		return "", err
+	}
+	return formatTime(t, format, layout), nil
+}
+
+// formatTime formats t with the Go layout when there is one, or else with
+// the strftime layout or TimeType named by format
+func formatTime(t time.Time, format, layout string) string {
+	if layout != "" {
+		return t.Format(layout)
+	}
+	if strings.Contains(format, "%") {
+		return strftime(t, format)
+	}
+	switch NewTimeType(format) {
+	case TimeTypeRfc3339:
+		return t.Format(time.RFC3339)
+	case TimeTypeUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeTypeUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.UTC().Format(utcLayout)
+	}
+}
+
+func truncateTime(t time.Time, unit string) (time.Time, error) {
+	y, mo, d := t.Date()
+	switch unit {
+	case "":
+		return t, nil
+	case "second":
+		return t.Truncate(time.Second), nil
+	case "minute":
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case "hour":
+		return time.Date(y, mo, d, t.Hour(), 0, 0, 0, t.Location()), nil
+	case "day":
+		return time.Date(y, mo, d, 0, 0, 0, 0, t.Location()), nil
+	case "month":
+		return time.Date(y, mo, 1, 0, 0, 0, 0, t.Location()), nil
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	default:
+		return t, fmt.Errorf("invalid truncation unit %q for time function", unit)
+	}
+}
+
+// Offset shifts times by calendar years, months and days, then by a duration
+type Offset struct {
+	Years, Months, Days int
+	Duration            time.Duration
+}
+
+// Add combines both offsets, failing when the result is out of range
+func (o Offset) Add(other Offset) (Offset, error) {
+	sum := Offset{
+		Years:  o.Years + other.Years,
+		Months: o.Months + other.Months,
+		Days:   o.Days + other.Days,
+	}
+	d, err := addDuration(o.Duration, int64(other.Duration), 1)
+	if err != nil {
+		return Offset{}, err
+	}
+	sum.Duration = d
+	return sum, sum.validate()
+}
+
+// maxOffsetYears bounds calendar offsets, whose parts could otherwise
+// overflow or move times past what time.Time can represent
+const maxOffsetYears = 10000
+
+func (o Offset) validate() error {
+	years := abs(o.Years) + abs(o.Months)/12 + abs(o.Days)/366
+	if years > maxOffsetYears {
+		return fmt.Errorf("time offset out of range, offsets are limited to %d years", maxOffsetYears)
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var errOffsetRange = errors.New("time offset out of range, durations are limited to about 292 years")
+
+// addDuration adds n units to d, failing instead of overflowing
+func addDuration(d time.Duration, n int64, unit time.Duration) (time.Duration, error) {
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return 0, errOffsetRange
+	}
+	v := time.Duration(n) * unit
+	if (v > 0 && d > math.MaxInt64-v) || (v < 0 && d < math.MinInt64-v) {
+		return 0, errOffsetRange
+	}
+	return d + v, nil
+}
+
+func (o Offset) Apply(t time.Time) time.Time {
+	return t.AddDate(o.Years, o.Months, o.Days).Add(o.Duration)
+}
+
+var (
+	offsetPattern = regexp.MustCompile(`^(now)?([+-](\d+(ms|s|m|h|d|w|M|y))+)*$`)
+	offsetPart    = regexp.MustCompile(`([+-]?)(\d+)(ms|s|m|h|d|w|M|y)`)
+)
+
+func isOffset(s string) bool {
+	return s != "" && offsetPattern.MatchString(s)
+}
+
+// ParseOffset parses offsets like "now+3d", "-2h" or "+1M-2d", where units are
+// ms, s, m (minutes), h, d, w, M (months) and y. A sign applies to every part
+// following it, so "-1d12h" is a day and a half ago. Offsets that do not fit
+// a time.Duration, or span more than maxOffsetYears, are an error.
+func ParseOffset(s string) (Offset, error) {
+	if !isOffset(s) {
+		return Offset{}, fmt.Errorf("invalid time offset %q", s)
+	}
+	var o Offset
+	sign := 1
+	for _, part := range offsetPart.FindAllStringSubmatch(strings.TrimPrefix(s, "now"), -1) {
+		switch part[1] {
+		case "+":
+			sign = 1
+		case "-":
+			sign = -1
+		}
+		n, err := strconv.ParseInt(part[2], 10, 64)
+		// durations are guarded by addDuration, calendar parts are bounded
+		// before being added up as days, months and years
+		calendar := strings.Contains("dwMy", part[3])
+		if err != nil || (calendar && n > maxOffsetYears*366) {
+			return Offset{}, fmt.Errorf("time offset %q out of range", s)
+		}
+		n *= int64(sign)
+		switch part[3] {
+		case "ms":
+			o.Duration, err = addDuration(o.Duration, n, time.Millisecond)
+		case "s":
+			o.Duration, err = addDuration(o.Duration, n, time.Second)
+		case "m":
+			o.Duration, err = addDuration(o.Duration, n, time.Minute)
+		case "h":
+			o.Duration, err = addDuration(o.Duration, n, time.Hour)
+		case "d":
+			o.Days += int(n)
+		case "w":
+			o.Days += 7 * int(n)
+		case "M":
+			o.Months += int(n)
+		case "y":
+			o.Years += int(n)
+		}
+		if err != nil {
+			return Offset{}, fmt.Errorf("%q: %w", s, err)
+		}
+		if err := o.validate(); err != nil {
+			return Offset{}, fmt.Errorf("%q: %w", s, err)
+		}
+	}
+	return o, nil
+}
+
+// parseLayouts are tried in order by ParseTime when no layout is given
+var parseLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseTime parses times coming from the request, like the raw JSON values
+// returned by requestBody. Numbers are unix seconds, or milliseconds when
+// too large to be seconds. Strings are parsed with the optional strftime
+// layout or layout=<Go layout>, or else as RFC 3339, ISO 8601 dates with or
+// without time, or RFC 1123.
+func ParseTime(value interface{}, layout ...string) (time.Time, error) {
+	var s string
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case int:
+		return unixTime(int64(v)), nil
+	case int64:
+		return unixTime(v), nil
+	case float64:
+		return unixTime(int64(v)), nil
+	case string:
+		s = strings.Trim(strings.TrimSpace(v), `"`)
+	default:
+		return time.Time{}, fmt.Errorf("invalid time %v", value)
+	}
+	if len(layout) > 0 {
+		l, err := parseLayout(layout[0])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Parse(l, s)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return unixTime(n), nil
+	}
+	for _, l := range parseLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func parseLayout(layout string) (string, error) {
+	if l, ok := strings.CutPrefix(layout, "layout="); ok {
+		return l, nil
+	}
+	if strings.Contains(layout, "%") {
+		return strftimeLayout(layout)
+	}
+	return "", fmt.Errorf("invalid layout %q, expected a strftime layout or layout=<Go layout>", layout)
+}
+
+// unixTime reads n as seconds, or as milliseconds when as seconds it would be past the year 5138
+func unixTime(n int64) time.Time {
+	if n > 1e11 || n < -1e11 {
+		return time.UnixMilli(n).UTC()
+	}
+	return time.Unix(n, 0).UTC()
+}
+
+var strftimeDirectives = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'L': ".000", 'f': ".000000",
+	'p': "PM", 'b': "Jan", 'B': "January", 'a': "Mon", 'A': "Monday",
+	'j': "002", 'z': "-0700", 'Z': "MST",
+}
+
+// strftimePart is either a directive, converted to its Go layout, or literal text
+type strftimePart struct {
+	layout  string
+	literal string
+}
+
+// strftimeParts splits a strftime layout, %L being milliseconds and %f
+// microseconds. Unknown directives are kept as literal text.
+func strftimeParts(format string) []strftimePart {
+	parts := []strftimePart{}
+	literal := strings.Builder{}
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, strftimePart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			literal.WriteByte(format[i])
+			continue
+		}
+		i++
+		if layout, ok := strftimeDirectives[format[i]]; ok {
+			flush()
+			parts = append(parts, strftimePart{layout: layout})
+		} else if format[i] == '%' {
+			literal.WriteByte('%')
+		} else {
+			literal.WriteString(format[i-1 : i+1])
+		}
+	}
+	flush()
+	return parts
+}
+
+// strftime formats t directive by directive, so literal text is written as it
+// is even when it looks like a Go layout, as "1" in "%Y week 1" does
+func strftime(t time.Time, format string) string {
+	var b strings.Builder
+	for _, part := range strftimeParts(format) {
+		switch {
+		case part.layout == "":
+			b.WriteString(part.literal)
+		case strings.HasPrefix(part.layout, "."):
+			// fractional seconds are only formatted after a dot
+			b.WriteString(t.Format(part.layout)[1:])
+		default:
+			b.WriteString(t.Format(part.layout))
+		}
+	}
+	return b.String()
+}
+
+// layoutProbe has no field in common with the reference time of Go layouts,
+// so formatting it changes any text holding a layout element
+var layoutProbe = time.Date(2000, time.December, 31, 10, 59, 58, 123456789, time.UTC)
+
+// strftimeLayout converts a strftime layout into a Go one for parsing. Go
+// layouts can not escape text, so literal text holding a layout element, like
+// "1" in "%Y week 1", is an error instead of being parsed as a time field.
+func strftimeLayout(format string) (string, error) {
+	var b strings.Builder
+	for _, part := range strftimeParts(format) {
+		if part.layout != "" {
+			// fractional seconds are only parsed after a dot, which may be literal text
+			if strings.HasPrefix(part.layout, ".") && strings.HasSuffix(b.String(), ".") {
+				b.WriteString(part.layout[1:])
+			} else {
+				b.WriteString(part.layout)
+			}
+			continue
+		}
+		if layoutProbe.Format(part.literal) != part.literal {
+			return "", fmt.Errorf("literal text %q of layout %q can not be parsed", part.literal, format)
+		}
+		b.WriteString(part.literal)
+	}
+	return b.String(), nil
 }
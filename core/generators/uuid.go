@@ -26,7 +26,14 @@ func NewUUIDType(uuidType string) UUIDType {
 	}
 }
 
+// UUID generates a random UUID, drawn from the source of the context when it
+// is seeded, see WithSeed. Seeded ULIDs are random bits too, their timestamp
+// included, so they repeat along with everything else.
 func UUID(ctx context.Context, options ...interface{}) (string, error) {
+	if IsSeeded(ctx) {
+		return seededUUID(ctx, options...)
+	}
+
 	// Signature UUID(ctx)
 	if len(options) <= 0 {
 		return uuid.NewString(), nil
@@ -49,3 +56,28 @@ func UUID(ctx context.Context, options ...interface{}) (string, error) {
 		return uuid.NewString(), nil
 	}
 }
+
+func seededUUID(ctx context.Context, options ...interface{}) (string, error) {
+	rnd := Rand(ctx)
+	t := UUIDTypeV4
+	if len(options) > 0 {
+		tRaw, ok := options[0].(string)
+		if !ok {
+			return "", errors.New("invalid type for uuid function")
+		}
+		t = NewUUIDType(tRaw)
+	}
+	if t == UUIDTypeULID {
+		id, err := ulid.New(uint64(rnd.Int63n(int64(ulid.MaxTime()))), rnd)
+		if err != nil {
+			return "", err
+		}
+		uu, _ := uuid.FromBytes(id.Bytes())
+		return uu.String(), nil
+	}
+	uu, err := uuid.NewRandomFromReader(rnd)
+	if err != nil {
+		return "", err
+	}
+	return uu.String(), nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// GraphQLRequest is a GraphQL operation sent over HTTP, either as the JSON body
+// of a POST request or as the query parameters of a GET request
+type GraphQLRequest struct {
+	Query         string          `json:"query"`
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables"`
+}
+
+// ParseGraphQLRequest reads the GraphQL operation of a request whose body was
+// already read. Requests that are not GraphQL result in an empty operation.
+func ParseGraphQLRequest(r *http.Request, body string) GraphQLRequest {
+	var g GraphQLRequest
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		g.Query = q.Get("query")
+		g.OperationName = q.Get("operationName")
+		if vars := q.Get("variables"); vars != "" {
+			g.Variables = json.RawMessage(vars)
+		}
+		return g
+	}
+	json.Unmarshal([]byte(body), &g)
+	return g
+}
+
+// Operation returns the name of the requested operation, falling back to the
+// name of the first operation of the query document when none was sent
+func (g GraphQLRequest) Operation() string {
+	if g.OperationName != "" {
+		return g.OperationName
+	}
+	return firstOperationName(g.Query)
+}
+
+// firstOperationName scans the top level of a query document for the first
+// operation definition, returning its name or "" when it is anonymous
+func firstOperationName(query string) string {
+	depth := 0
+	expectName, inFragment := false, false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			i = skipString(query, i)
+		case c == '{' || c == '(':
+			if depth == 0 && !inFragment && (c == '{' || expectName) {
+				// shorthand query, or an operation without a name
+				return ""
+			}
+			if depth == 0 && c == '{' {
+				inFragment = false
+			}
+			depth++
+		case c == '}' || c == ')':
+			depth--
+		case depth == 0 && isNameStart(c):
+			start := i
+			for i+1 < len(query) && isNameChar(query[i+1]) {
+				i++
+			}
+			name := query[start : i+1]
+			switch {
+			case inFragment:
+			case expectName:
+				return name
+			case name == "fragment":
+				inFragment = true
+			case name == "query" || name == "mutation" || name == "subscription":
+				expectName = true
+			}
+		case expectName && c == '@':
+			return ""
+		}
+	}
+	return ""
+}
+
+// skipString returns the index of the quote closing the string starting at i
+func skipString(query string, i int) int {
+	if strings.HasPrefix(query[i:], `"""`) {
+		end := strings.Index(query[i+3:], `"""`)
+		if end < 0 {
+			return len(query)
+		}
+		return i + 3 + end + 2
+	}
+	for i++; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return i
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// wrapGraphQLData places a rendered GRAPHQL body under "data", unless it is
+// already a complete GraphQL response with data or errors
+func wrapGraphQLData(body string) string {
+	if gjson.Get(body, "data").Exists() || gjson.Get(body, "errors").Exists() {
+		return body
+	}
+	return `{"data":` + body + `}`
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLRequest_Operation(t *testing.T) {
+	cases := map[string]struct {
+		req      GraphQLRequest
+		expected string
+	}{
+		"explicit operation name": {
+			req:      GraphQLRequest{OperationName: "GetUser", Query: "query Other { user { id } }"},
+			expected: "GetUser",
+		},
+		"named query": {
+			req:      GraphQLRequest{Query: "query GetUser($id: ID!) { user(id: $id) { id } }"},
+			expected: "GetUser",
+		},
+		"named mutation after comments and fragments": {
+			req: GraphQLRequest{Query: `
+				# query Commented { x }
+				fragment UserFields on User { id name(format: "{") }
+				mutation UpdateUser @audit { updateUser { ...UserFields } }`},
+			expected: "UpdateUser",
+		},
+		"anonymous operation": {
+			req:      GraphQLRequest{Query: "query ($id: ID!) { user(id: $id) { id } }"},
+			expected: "",
+		},
+		"shorthand query": {
+			req:      GraphQLRequest{Query: "{ users { id } }"},
+			expected: "",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.req.Operation())
+		})
+	}
+}
@@ -7,3 +7,12 @@ import (
 type Loader interface {
 	Load(r *http.Request) ([]RouteDefinition, error)
 }
+
+// VersionedLoader is a Loader that also reports the version of the definitions
+// it serves for a request. Versions start at 1 and change whenever the
+// definitions served for the request do, so what was built for a version,
+// like the routers of NewDynamicRouter, can be reused until it changes.
+type VersionedLoader interface {
+	Loader
+	LoadVersion(r *http.Request) ([]RouteDefinition, uint64, error)
+}
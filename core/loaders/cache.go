@@ -0,0 +1,112 @@
+package loaders
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/pkg/path"
+)
+
+// CachingLoader decorates a core.Loader caching its definitions by path prefix,
+// as extracted by path.ExtractPrefix, for a limited time.
+// Each cached entry gets a new version, which allows the dynamic router to
+// reuse the router it compiled for it while it is cached.
+type CachingLoader struct {
+	loader     core.Loader
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	version uint64
+}
+
+type cacheEntry struct {
+	defs      []core.RouteDefinition
+	version   uint64
+	expiresAt time.Time
+}
+
+// NewCachingLoader caches the definitions of loader for ttl.
+// A non positive maxEntries does not limit the amount of cached prefixes,
+// otherwise the entry closest to expiring is evicted to make room for a new one.
+func NewCachingLoader(loader core.Loader, ttl time.Duration, maxEntries int) *CachingLoader {
+	return &CachingLoader{
+		loader:     loader,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// Ensures CachingLoader implements core.VersionedLoader
+var (
+	_ core.VersionedLoader = (*CachingLoader)(nil)
+)
+
+func (l *CachingLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	defs, _, err := l.LoadVersion(r)
+	return defs, err
+}
+
+// LoadVersion returns the cached definitions of the request path prefix,
+// loading them again once they expire under a new version
+func (l *CachingLoader) LoadVersion(r *http.Request) ([]core.RouteDefinition, uint64, error) {
+	prefix := path.ExtractPrefix(r.URL.Path)
+
+	l.mu.Lock()
+	entry, ok := l.entries[prefix]
+	l.mu.Unlock()
+	if ok && l.now().Before(entry.expiresAt) {
+		return entry.defs, entry.version, nil
+	}
+
+	defs, err := l.loader.Load(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.entries[prefix]; !ok && l.maxEntries > 0 && len(l.entries) >= l.maxEntries {
+		l.evict()
+	}
+	l.version++
+	l.entries[prefix] = cacheEntry{defs: defs, version: l.version, expiresAt: l.now().Add(l.ttl)}
+	return defs, l.version, nil
+}
+
+// Invalidate drops the cached definitions of a path prefix such as "/items"
+func (l *CachingLoader) Invalidate(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, prefix)
+}
+
+// InvalidateAll drops every cached definition
+func (l *CachingLoader) InvalidateAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = make(map[string]cacheEntry)
+}
+
+// evict removes the entry closest to expiring, the caller must hold the lock
+func (l *CachingLoader) evict() {
+	var (
+		oldest    string
+		expiresAt time.Time
+		found     bool
+	)
+	for prefix, entry := range l.entries {
+		if !found || entry.expiresAt.Before(expiresAt) {
+			oldest, expiresAt, found = prefix, entry.expiresAt, true
+		}
+	}
+	if found {
+		delete(l.entries, oldest)
+	}
+}
@@ -0,0 +1,77 @@
+package loaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingLoader struct {
+	calls map[string]int
+}
+
+func (l *countingLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	l.calls[r.URL.Path]++
+	return []core.RouteDefinition{*core.NewRouteDefinition(r.URL.Path, "GET", core.RouteResponse{})}, nil
+}
+
+func TestCachingLoader(t *testing.T) {
+	now := time.Now()
+	newLoader := func(maxEntries int) (*CachingLoader, *countingLoader) {
+		inner := &countingLoader{calls: make(map[string]int)}
+		l := NewCachingLoader(inner, time.Minute, maxEntries)
+		l.now = func() time.Time { return now }
+		return l, inner
+	}
+	load := func(l core.Loader, path string) []core.RouteDefinition {
+		defs, err := l.Load(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Nil(t, err)
+		return defs
+	}
+
+	t.Run("should cache definitions by path prefix until ttl expires", func(t *testing.T) {
+		l, inner := newLoader(0)
+		first := load(l, "/items")
+		second := load(l, "/items/1")
+		assert.Equal(t, 1, inner.calls["/items"])
+		assert.Equal(t, 0, inner.calls["/items/1"])
+		assert.Same(t, &first[0], &second[0])
+		_, version, _ := l.LoadVersion(httptest.NewRequest(http.MethodGet, "/items", nil))
+
+		now = now.Add(2 * time.Minute)
+		load(l, "/items/1")
+		assert.Equal(t, 1, inner.calls["/items/1"])
+		_, reloaded, _ := l.LoadVersion(httptest.NewRequest(http.MethodGet, "/items", nil))
+		assert.NotEqual(t, version, reloaded)
+	})
+
+	t.Run("should reload invalidated prefixes", func(t *testing.T) {
+		l, inner := newLoader(0)
+		load(l, "/items")
+		load(l, "/orders")
+		l.Invalidate("/items")
+		load(l, "/items")
+		load(l, "/orders")
+		assert.Equal(t, 2, inner.calls["/items"])
+		assert.Equal(t, 1, inner.calls["/orders"])
+
+		l.InvalidateAll()
+		load(l, "/orders")
+		assert.Equal(t, 2, inner.calls["/orders"])
+	})
+
+	t.Run("should evict entries beyond max entries", func(t *testing.T) {
+		l, inner := newLoader(1)
+		load(l, "/items")
+		now = now.Add(time.Second)
+		load(l, "/orders")
+		load(l, "/orders")
+		load(l, "/items")
+		assert.Equal(t, 2, inner.calls["/items"])
+		assert.Equal(t, 1, inner.calls["/orders"])
+	})
+}
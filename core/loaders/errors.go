@@ -0,0 +1,10 @@
+package loaders
+
+import "errors"
+
+var (
+	ErrUnsupportedFileFormat = errors.New("unsupported definitions file format")
+	ErrMissingPathOrMethod   = errors.New("route definition requires a path and a method")
+	ErrMissingResponse       = errors.New("route definition requires a response")
+	ErrRouteNotFound         = errors.New("route definition not found")
+)
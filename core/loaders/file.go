@@ -0,0 +1,659 @@
+package loaders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"gopkg.in/yaml.v3"
+)
+
+// FileLoader serves route definitions read from YAML or JSON files
+type FileLoader struct {
+	defs []core.RouteDefinition
+}
+
+// NewFileLoader reads the route definitions of every file and directory in paths
+func NewFileLoader(paths ...string) (*FileLoader, error) {
+	defs, err := ReadDefinitions(paths...)
+	if err != nil {
+		return nil, err
+	}
+	return &FileLoader{defs}, nil
+}
+
+// Ensures FileLoader implements core.VersionedLoader
+var (
+	_ core.VersionedLoader = (*FileLoader)(nil)
+)
+
+func (l *FileLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	return l.defs, nil
+}
+
+// LoadVersion returns the definitions, which were read once and never change version
+func (l *FileLoader) LoadVersion(r *http.Request) ([]core.RouteDefinition, uint64, error) {
+	return l.defs, 1, nil
+}
+
+// ReadDefinitions reads route definitions from files and directories.
+// Directories are read recursively and only files with a .yaml, .yml or .json
+// extension are considered, in lexical order.
+func ReadDefinitions(paths ...string) ([]core.RouteDefinition, error) {
+	files, err := definitionFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	defs := []core.RouteDefinition{}
+	for _, file := range files {
+		fileDefs, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, fileDefs...)
+	}
+	if err := core.ValidateDefinitions(defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// WriteFile writes route definitions to a YAML or JSON file, chosen by its extension
+func WriteFile(path string, defs []core.RouteDefinition) error {
+	format, ok := FormatFromExt(path)
+	if !ok {
+		return fmt.Errorf("%s: %w", path, ErrUnsupportedFileFormat)
+	}
+	data, err := Encode(defs, format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadFile reads the route definitions of a single YAML or JSON file
+func ReadFile(path string) ([]core.RouteDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format, ok := FormatFromExt(path)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", path, ErrUnsupportedFileFormat)
+	}
+	defs, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return defs, nil
+}
+
+func definitionFiles(paths ...string) ([]string, error) {
+	files := []string{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		dirFiles := []string{}
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if _, ok := FormatFromExt(path); ok && !d.IsDir() {
+				dirFiles = append(dirFiles, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+type Format string
+
+const (
+	FORMAT_YAML Format = "YAML"
+	FORMAT_JSON Format = "JSON"
+)
+
+// FormatFromExt returns the format of a file from its extension, so other
+// file readers like the gRPC stub one accept the same extensions
+func FormatFromExt(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FORMAT_YAML, true
+	case ".json":
+		return FORMAT_JSON, true
+	default:
+		return "", false
+	}
+}
+
+// Parse decodes a document with a top level "routes" list into route definitions
+func Parse(data []byte, format Format) ([]core.RouteDefinition, error) {
+	var doc FileDocument
+	var err error
+	switch format {
+	case FORMAT_YAML:
+		err = yaml.Unmarshal(data, &doc)
+	case FORMAT_JSON:
+		err = json.Unmarshal(data, &doc)
+	default:
+		err = ErrUnsupportedFileFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.Definitions()
+}
+
+// Encode encodes route definitions into a document with a top level "routes" list
+func Encode(defs []core.RouteDefinition, format Format) ([]byte, error) {
+	doc := NewFileDocument(defs)
+	switch format {
+	case FORMAT_YAML:
+		return yaml.Marshal(doc)
+	case FORMAT_JSON:
+		return json.MarshalIndent(doc, "", "  ")
+	default:
+		return nil, ErrUnsupportedFileFormat
+	}
+}
+
+// FileDocument is the representation of a definitions file
+type FileDocument struct {
+	Routes []FileRouteDefinition `json:"routes" yaml:"routes"`
+}
+
+type FileRouteDefinition struct {
+	Name          string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Path          string              `json:"path" yaml:"path"`
+	Method        string              `json:"method" yaml:"method"`
+	Matchers      []FileMatcher       `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Response      *FileRouteResponse  `json:"response,omitempty" yaml:"response,omitempty"`
+	Responses     []FileRouteResponse `json:"responses,omitempty" yaml:"responses,omitempty"`
+	ResponseMode  string              `json:"response_mode,omitempty" yaml:"response_mode,omitempty"`
+	Scenario      string              `json:"scenario,omitempty" yaml:"scenario,omitempty"`
+	RequiredState string              `json:"required_state,omitempty" yaml:"required_state,omitempty"`
+	NewState      string              `json:"new_state,omitempty" yaml:"new_state,omitempty"`
+	Seed          string              `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+type FileMatcher struct {
+	Source   string `json:"source" yaml:"source"`
+	Key      string `json:"key" yaml:"key"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// FileRouteResponse mirrors core.RouteResponse.
+// Body may be a string or any structured value, which is encoded as JSON.
+// StatusCode defaults to 200 when omitted.
+// Delay is a duration string such as "150ms" or "2s".
+type FileRouteResponse struct {
+	Type       string            `json:"type" yaml:"type"`
+	StatusCode int               `json:"status_code" yaml:"status_code"`
+	Body       interface{}       `json:"body,omitempty" yaml:"body,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Delay      string            `json:"delay,omitempty" yaml:"delay,omitempty"`
+	Upstream   string            `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	// DelayProfile, when set, takes precedence over Delay
+	DelayProfile *FileDelayProfile    `json:"delay_profile,omitempty" yaml:"delay_profile,omitempty"`
+	Faults       []FileFault          `json:"faults,omitempty" yaml:"faults,omitempty"`
+	Throttle     *FileThrottle        `json:"throttle,omitempty" yaml:"throttle,omitempty"`
+	Events       []FileEvent          `json:"events,omitempty" yaml:"events,omitempty"`
+	Repeat       int                  `json:"repeat,omitempty" yaml:"repeat,omitempty"`
+	WebSocket    *FileWebSocketScript `json:"websocket,omitempty" yaml:"websocket,omitempty"`
+}
+
+// FileEvent mirrors core.Event. Like response bodies, Data may be a string
+// or any structured value, which is encoded as JSON.
+type FileEvent struct {
+	Name  string      `json:"event,omitempty" yaml:"event,omitempty"`
+	ID    string      `json:"id,omitempty" yaml:"id,omitempty"`
+	Data  interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+	Delay string      `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+func (f FileEvent) Event() (*core.Event, error) {
+	data, err := EncodeBody(f.Data)
+	if err != nil {
+		return nil, err
+	}
+	delay, err := ParseDuration(f.Delay)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Event{Name: f.Name, ID: f.ID, Data: data, Delay: delay}, nil
+}
+
+// FileThrottle mirrors core.Throttle with durations as strings
+type FileThrottle struct {
+	BytesPerSecond int    `json:"bytes_per_second,omitempty" yaml:"bytes_per_second,omitempty"`
+	ChunkSize      int    `json:"chunk_size,omitempty" yaml:"chunk_size,omitempty"`
+	ChunkDelay     string `json:"chunk_delay,omitempty" yaml:"chunk_delay,omitempty"`
+	FirstByteDelay string `json:"first_byte_delay,omitempty" yaml:"first_byte_delay,omitempty"`
+}
+
+func (f FileThrottle) Throttle() (*core.Throttle, error) {
+	chunkDelay, err := ParseDuration(f.ChunkDelay)
+	if err != nil {
+		return nil, err
+	}
+	firstByteDelay, err := ParseDuration(f.FirstByteDelay)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Throttle{
+		BytesPerSecond: f.BytesPerSecond,
+		ChunkSize:      f.ChunkSize,
+		ChunkDelay:     chunkDelay,
+		FirstByteDelay: firstByteDelay,
+	}, nil
+}
+
+// FileFault mirrors core.Fault, Chance being a percentage
+type FileFault struct {
+	Type         string  `json:"type" yaml:"type"`
+	Chance       float64 `json:"chance" yaml:"chance"`
+	DripBytes    int     `json:"drip_bytes,omitempty" yaml:"drip_bytes,omitempty"`
+	DripInterval string  `json:"drip_interval,omitempty" yaml:"drip_interval,omitempty"`
+}
+
+func (f FileFault) Fault() (*core.Fault, error) {
+	faultType, err := core.NewFaultType(f.Type)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := ParseDuration(f.DripInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Fault{
+		Type:         faultType,
+		Chance:       f.Chance,
+		DripBytes:    f.DripBytes,
+		DripInterval: interval,
+	}, nil
+}
+
+// FileDelayProfile mirrors core.DelayProfile with durations as strings
+type FileDelayProfile struct {
+	Distribution string  `json:"distribution" yaml:"distribution"`
+	Fixed        string  `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+	Min          string  `json:"min,omitempty" yaml:"min,omitempty"`
+	Max          string  `json:"max,omitempty" yaml:"max,omitempty"`
+	Mean         string  `json:"mean,omitempty" yaml:"mean,omitempty"`
+	StdDev       string  `json:"std_dev,omitempty" yaml:"std_dev,omitempty"`
+	Median       string  `json:"median,omitempty" yaml:"median,omitempty"`
+	Sigma        float64 `json:"sigma,omitempty" yaml:"sigma,omitempty"`
+	P50          string  `json:"p50,omitempty" yaml:"p50,omitempty"`
+	P99          string  `json:"p99,omitempty" yaml:"p99,omitempty"`
+}
+
+// NewFileDocument creates the file representation of defs
+func NewFileDocument(defs []core.RouteDefinition) FileDocument {
+	routes := make([]FileRouteDefinition, len(defs))
+	for i, def := range defs {
+		routes[i] = NewFileRouteDefinition(def)
+	}
+	return FileDocument{Routes: routes}
+}
+
+func (d FileDocument) Definitions() ([]core.RouteDefinition, error) {
+	defs := make([]core.RouteDefinition, len(d.Routes))
+	for i, route := range d.Routes {
+		def, err := route.Definition()
+		if err != nil {
+			return nil, fmt.Errorf("route %d (%s %s): %w", i, route.Method, route.Path, err)
+		}
+		defs[i] = *def
+	}
+	return defs, nil
+}
+
+// NewFileRouteDefinition creates the file representation of a route definition
+func NewFileRouteDefinition(def core.RouteDefinition) FileRouteDefinition {
+	f := FileRouteDefinition{
+		Path:          def.Path,
+		Method:        def.Method,
+		ResponseMode:  def.ResponseMode.String(),
+		Scenario:      def.Scenario,
+		RequiredState: def.RequiredState,
+		NewState:      def.NewState,
+		Seed:          def.Seed,
+	}
+	for _, m := range def.Matchers {
+		f.Matchers = append(f.Matchers, FileMatcher{
+			Source:   m.Source.String(),
+			Key:      m.Key,
+			Operator: m.Operator.String(),
+			Value:    m.Value,
+		})
+	}
+	if len(def.Responses) == 0 {
+		res := NewFileRouteResponse(def.Response)
+		f.Response = &res
+	}
+	for _, r := range def.Responses {
+		f.Responses = append(f.Responses, NewFileRouteResponse(r))
+	}
+	return f
+}
+
+func (f FileRouteDefinition) Definition() (*core.RouteDefinition, error) {
+	if f.Path == "" || f.Method == "" {
+		return nil, ErrMissingPathOrMethod
+	}
+	if f.Response == nil && len(f.Responses) == 0 {
+		return nil, ErrMissingResponse
+	}
+
+	def := core.NewRouteDefinition(f.Path, strings.ToUpper(f.Method), core.RouteResponse{})
+	def.Scenario = f.Scenario
+	def.RequiredState = f.RequiredState
+	def.NewState = f.NewState
+	def.Seed = f.Seed
+
+	for _, m := range f.Matchers {
+		matcher, err := m.Matcher()
+		if err != nil {
+			return nil, err
+		}
+		def.Matchers = append(def.Matchers, *matcher)
+	}
+
+	if f.Response != nil {
+		res, err := f.Response.Response()
+		if err != nil {
+			return nil, err
+		}
+		def.Response = *res
+	}
+	for _, r := range f.Responses {
+		res, err := r.Response()
+		if err != nil {
+			return nil, err
+		}
+		def.Responses = append(def.Responses, *res)
+	}
+	if f.ResponseMode != "" {
+		mode, err := core.NewResponseMode(f.ResponseMode)
+		if err != nil {
+			return nil, err
+		}
+		def.ResponseMode = mode
+	}
+	return def, nil
+}
+
+func (f FileMatcher) Matcher() (*core.RequestMatcher, error) {
+	source, err := core.NewMatcherSource(f.Source)
+	if err != nil {
+		return nil, err
+	}
+	operator, err := core.NewMatchOperator(f.Operator)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewRequestMatcher(source, f.Key, operator, f.Value), nil
+}
+
+// NewFileRouteResponse creates the file representation of a route response
+func NewFileRouteResponse(res core.RouteResponse) FileRouteResponse {
+	f := FileRouteResponse{
+		Type:       res.Type.String(),
+		StatusCode: res.StatusCode,
+		Headers:    res.Headers,
+		Upstream:   res.Upstream,
+	}
+	if res.Body != "" {
+		f.Body = res.Body
+	}
+	if res.Delay > 0 {
+		f.Delay = res.Delay.String()
+	}
+	if res.DelayProfile != nil {
+		profile := NewFileDelayProfile(*res.DelayProfile)
+		f.DelayProfile = &profile
+	}
+	for _, fault := range res.Faults {
+		f.Faults = append(f.Faults, FileFault{
+			Type:         fault.Type.String(),
+			Chance:       fault.Chance,
+			DripBytes:    fault.DripBytes,
+			DripInterval: durationString(fault.DripInterval),
+		})
+	}
+	for _, e := range res.Events {
+		fe := FileEvent{Name: e.Name, ID: e.ID, Delay: durationString(e.Delay)}
+		if e.Data != "" {
+			fe.Data = e.Data
+		}
+		f.Events = append(f.Events, fe)
+	}
+	f.Repeat = res.Repeat
+	if res.WebSocket != nil {
+		script := NewFileWebSocketScript(*res.WebSocket)
+		f.WebSocket = &script
+	}
+	if res.Throttle != nil {
+		f.Throttle = &FileThrottle{
+			BytesPerSecond: res.Throttle.BytesPerSecond,
+			ChunkSize:      res.Throttle.ChunkSize,
+			ChunkDelay:     durationString(res.Throttle.ChunkDelay),
+			FirstByteDelay: durationString(res.Throttle.FirstByteDelay),
+		}
+	}
+	return f
+}
+
+// NewFileDelayProfile creates the file representation of a delay profile
+func NewFileDelayProfile(p core.DelayProfile) FileDelayProfile {
+	return FileDelayProfile{
+		Distribution: p.Distribution.String(),
+		Fixed:        durationString(p.Fixed),
+		Min:          durationString(p.Min),
+		Max:          durationString(p.Max),
+		Mean:         durationString(p.Mean),
+		StdDev:       durationString(p.StdDev),
+		Median:       durationString(p.Median),
+		Sigma:        p.Sigma,
+		P50:          durationString(p.P50),
+		P99:          durationString(p.P99),
+	}
+}
+
+func (f FileDelayProfile) Profile() (*core.DelayProfile, error) {
+	distribution, err := core.NewDelayDistribution(f.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	p := &core.DelayProfile{Distribution: distribution, Sigma: f.Sigma}
+	fields := []struct {
+		raw string
+		dst *time.Duration
+	}{
+		{f.Fixed, &p.Fixed}, {f.Min, &p.Min}, {f.Max, &p.Max},
+		{f.Mean, &p.Mean}, {f.StdDev, &p.StdDev}, {f.Median, &p.Median},
+		{f.P50, &p.P50}, {f.P99, &p.P99},
+	}
+	for _, field := range fields {
+		if *field.dst, err = ParseDuration(field.raw); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func durationString(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// ParseDuration parses durations of definition files, where empty means none
+func ParseDuration(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+func (f FileRouteResponse) Response() (*core.RouteResponse, error) {
+	responseType, err := core.NewRouteResponseType(f.Type)
+	if err != nil {
+		return nil, err
+	}
+	delay, err := ParseDuration(f.Delay)
+	if err != nil {
+		return nil, err
+	}
+	body, err := EncodeBody(f.Body)
+	if err != nil {
+		return nil, err
+	}
+	res := core.NewRouteResponse(responseType, f.StatusCode, body, f.Headers, delay)
+	res.Upstream = f.Upstream
+	if f.DelayProfile != nil {
+		res.DelayProfile, err = f.DelayProfile.Profile()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, ff := range f.Faults {
+		fault, err := ff.Fault()
+		if err != nil {
+			return nil, err
+		}
+		res.Faults = append(res.Faults, *fault)
+	}
+	if f.Throttle != nil {
+		res.Throttle, err = f.Throttle.Throttle()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, fe := range f.Events {
+		event, err := fe.Event()
+		if err != nil {
+			return nil, err
+		}
+		res.Events = append(res.Events, *event)
+	}
+	res.Repeat = f.Repeat
+	if f.WebSocket != nil {
+		res.WebSocket, err = f.WebSocket.Script()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// EncodeBody encodes structured bodies as JSON. Template actions inside string
+// values are written as they are, as json.Marshal would escape the quotes of
+// their arguments and the encoded body could not be parsed as a template.
+func EncodeBody(body interface{}) (string, error) {
+	switch b := body.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return b, nil
+	default:
+		sb := &strings.Builder{}
+		if err := encodeValue(sb, b); err != nil {
+			return "", err
+		}
+		return sb.String(), nil
+	}
+}
+
+func encodeValue(sb *strings.Builder, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return encodeString(sb, v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		sb.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if err := encodeString(sb, k); err != nil {
+				return err
+			}
+			sb.WriteByte(':')
+			if err := encodeValue(sb, v[k]); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte('}')
+		return nil
+	case []interface{}:
+		sb.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if err := encodeValue(sb, item); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte(']')
+		return nil
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		sb.Write(raw)
+		return nil
+	}
+}
+
+// encodeString writes s as a JSON string, escaping everything but template actions
+func encodeString(sb *strings.Builder, s string) error {
+	sb.WriteByte('"')
+	for s != "" {
+		start := strings.Index(s, "{{")
+		end := -1
+		if start >= 0 {
+			end = strings.Index(s[start:], "}}")
+		}
+		if end < 0 {
+			start, end = len(s), len(s)
+		} else {
+			end += start + len("}}")
+		}
+		raw, err := json.Marshal(s[:start])
+		if err != nil {
+			return err
+		}
+		sb.Write(raw[1 : len(raw)-1])
+		sb.WriteString(s[start:end])
+		s = s[end:]
+	}
+	sb.WriteByte('"')
+	return nil
+}
@@ -0,0 +1,153 @@
+package loaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlDefinitions = `
+routes:
+  - path: /items/{id}
+    method: get
+    matchers:
+      - source: HEADER
+        key: X-Tenant
+        operator: EQUALS
+        value: acme
+    response:
+      type: DYNAMIC
+      status_code: 200
+      delay: 150ms
+      body:
+        id: '{{ requestVar "id" }}'
+      headers:
+        Item-ID: '{{ requestVar "id" }}'
+`
+
+const jsonDefinitions = `{
+  "routes": [
+    {
+      "path": "/health",
+      "method": "GET",
+      "response_mode": "CYCLIC",
+      "responses": [
+        { "type": "STATIC", "status_code": 503 },
+        { "type": "STATIC", "status_code": 200, "body": "ok" }
+      ]
+    }
+  ]
+}`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFile(t *testing.T) {
+	t.Run("should map yaml definitions", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "items.yaml", yamlDefinitions)
+		defs, err := ReadFile(path)
+		assert.Nil(t, err)
+		assert.Len(t, defs, 1)
+		def := defs[0]
+		assert.Equal(t, "/items/{id}", def.Path)
+		assert.Equal(t, "GET", def.Method)
+		assert.Equal(t, []core.RequestMatcher{
+			{Source: core.MATCHER_SOURCE_HEADER, Key: "X-Tenant", Operator: core.MATCH_OPERATOR_EQUALS, Value: "acme"},
+		}, def.Matchers)
+		assert.Equal(t, core.RESPONSE_TYPE_DYNAMIC, def.Response.Type)
+		assert.Equal(t, 200, def.Response.StatusCode)
+		assert.Equal(t, 150*time.Millisecond, def.Response.Delay)
+		assert.Equal(t, `{"id":"{{ requestVar "id" }}"}`, def.Response.Body)
+		assert.Equal(t, map[string]string{"Item-ID": `{{ requestVar "id" }}`}, def.Response.Headers)
+	})
+
+	t.Run("should map json definitions", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "health.json", jsonDefinitions)
+		defs, err := ReadFile(path)
+		assert.Nil(t, err)
+		assert.Len(t, defs, 1)
+		assert.Equal(t, core.RESPONSE_MODE_CYCLIC, defs[0].ResponseMode)
+		assert.Len(t, defs[0].Responses, 2)
+		assert.Equal(t, "ok", defs[0].Responses[1].Body)
+	})
+
+	t.Run("should keep template actions of structured bodies parseable", func(t *testing.T) {
+		path := writeFile(t, t.TempDir(), "tenant.yaml", `
+routes:
+  - path: /tenant
+    method: GET
+    response:
+      type: DYNAMIC
+      status_code: 200
+      body:
+        tenant: '{{ requestHeader "X-Tenant" }}'
+        greeting: 'say "hi" to {{ requestHeader "X-Tenant" }}'
+        tags: ['{{ requestHeader "X-Tenant" }}', 1]
+`)
+		defs, err := ReadFile(path)
+		assert.Nil(t, err)
+		assert.Nil(t, defs[0].Compile())
+
+		r := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		r.Header.Set("X-Tenant", "acme")
+		res, err := defs[0].Response.BuildResponse(r)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"tenant": "acme", "greeting": "say \"hi\" to acme", "tags": ["acme", 1]}`, *res.Body)
+	})
+
+	t.Run("should fail on invalid definitions", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := ReadFile(writeFile(t, dir, "a.yaml", "routes:\n  - path: /a\n    method: GET\n"))
+		assert.ErrorIs(t, err, ErrMissingResponse)
+
+		_, err = ReadFile(writeFile(t, dir, "b.yaml", "routes:\n  - path: /b\n    method: GET\n    response:\n      type: OTHER\n"))
+		assert.ErrorIs(t, err, core.ErrInvalidRouteResponseType)
+
+		_, err = ReadFile(writeFile(t, dir, "c.txt", ""))
+		assert.ErrorIs(t, err, ErrUnsupportedFileFormat)
+	})
+}
+
+func TestReadDefinitions(t *testing.T) {
+	t.Run("should answer ok when the status code is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "items.yaml", "routes:\n  - path: /items\n    method: GET\n    response:\n      type: STATIC\n      body: ok\n")
+		defs, err := ReadDefinitions(dir)
+		assert.Nil(t, err)
+		res, err := defs[0].Response.BuildResponse(httptest.NewRequest(http.MethodGet, "/items", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		writeFile(t, dir, "items.yaml", "routes:\n  - path: /items\n    method: GET\n    response:\n      type: STATIC\n      status_code: 42\n")
+		_, err = ReadDefinitions(dir)
+		assert.ErrorIs(t, err, core.ErrInvalidStatusCode)
+	})
+
+	t.Run("should read every definition file of a directory in order", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "b/items.yaml", yamlDefinitions)
+		writeFile(t, dir, "a/health.json", jsonDefinitions)
+		writeFile(t, dir, "notes.md", "ignored")
+
+		defs, err := ReadDefinitions(dir)
+		assert.Nil(t, err)
+		assert.Len(t, defs, 2)
+		assert.Equal(t, "/health", defs[0].Path)
+		assert.Equal(t, "/items/{id}", defs[1].Path)
+	})
+}
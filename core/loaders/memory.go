@@ -0,0 +1,140 @@
+package loaders
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/google/uuid"
+)
+
+// StoredRoute is a route definition kept by MemoryLoader under a generated ID
+type StoredRoute struct {
+	ID         string
+	Definition core.RouteDefinition
+}
+
+// MemoryLoader serves route definitions kept in memory that can be changed at runtime
+type MemoryLoader struct {
+	mu      sync.RWMutex
+	initial []core.RouteDefinition
+	routes  []StoredRoute
+	defs    []core.RouteDefinition
+	version uint64
+}
+
+// NewMemoryLoader creates a loader serving defs, which are also the definitions restored by Reset
+func NewMemoryLoader(defs ...core.RouteDefinition) *MemoryLoader {
+	l := &MemoryLoader{initial: defs}
+	l.Reset()
+	return l
+}
+
+// Ensures MemoryLoader implements core.VersionedLoader
+var (
+	_ core.VersionedLoader = (*MemoryLoader)(nil)
+)
+
+func (l *MemoryLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	defs, _, err := l.LoadVersion(r)
+	return defs, err
+}
+
+// LoadVersion returns the stored definitions, whose version changes on every change
+func (l *MemoryLoader) LoadVersion(r *http.Request) ([]core.RouteDefinition, uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.defs, l.version, nil
+}
+
+// List returns every stored route in the order they are matched
+func (l *MemoryLoader) List() []StoredRoute {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	routes := make([]StoredRoute, len(l.routes))
+	copy(routes, l.routes)
+	return routes
+}
+
+func (l *MemoryLoader) Get(id string) (StoredRoute, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	i := l.indexOf(id)
+	if i < 0 {
+		return StoredRoute{}, ErrRouteNotFound
+	}
+	return l.routes[i], nil
+}
+
+// Create validates and appends a definition, returning it with its generated ID
+func (l *MemoryLoader) Create(def core.RouteDefinition) (StoredRoute, error) {
+	if err := def.Validate(); err != nil {
+		return StoredRoute{}, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	route := StoredRoute{ID: uuid.NewString(), Definition: def}
+	l.routes = append(l.routes, route)
+	l.refresh()
+	return route, nil
+}
+
+// Update validates and replaces the definition stored under id, keeping its position
+func (l *MemoryLoader) Update(id string, def core.RouteDefinition) (StoredRoute, error) {
+	if err := def.Validate(); err != nil {
+		return StoredRoute{}, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := l.indexOf(id)
+	if i < 0 {
+		return StoredRoute{}, ErrRouteNotFound
+	}
+	l.routes[i].Definition = def
+	l.refresh()
+	return l.routes[i], nil
+}
+
+func (l *MemoryLoader) Delete(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := l.indexOf(id)
+	if i < 0 {
+		return ErrRouteNotFound
+	}
+	l.routes = append(l.routes[:i:i], l.routes[i+1:]...)
+	l.refresh()
+	return nil
+}
+
+// Reset drops every change, restoring the definitions the loader was created with
+func (l *MemoryLoader) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.routes = make([]StoredRoute, len(l.initial))
+	for i, def := range l.initial {
+		l.routes[i] = StoredRoute{ID: uuid.NewString(), Definition: def}
+	}
+	l.refresh()
+}
+
+// indexOf returns the position of the route with the given id, the caller must hold the lock
+func (l *MemoryLoader) indexOf(id string) int {
+	for i, route := range l.routes {
+		if route.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// refresh rebuilds the served definitions under a new version, the caller must hold the lock.
+// A new slice is built on every change so callers keep the one they were given.
+func (l *MemoryLoader) refresh() {
+	defs := make([]core.RouteDefinition, len(l.routes))
+	for i, route := range l.routes {
+		defs[i] = route.Definition
+	}
+	l.defs = defs
+	l.version++
+}
@@ -0,0 +1,162 @@
+package loaders
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+)
+
+const DefaultWatchInterval = time.Second
+
+// WatchingLoader serves route definitions read from files and directories,
+// reloading them whenever a definitions file is created, changed or removed.
+// Changes are detected by polling file modification times and sizes.
+// When the new definitions fail to parse or validate, the previous ones are
+// kept and the reason is logged.
+type WatchingLoader struct {
+	paths    []string
+	interval time.Duration
+	table    atomic.Pointer[routeTable]
+	mu       sync.Mutex
+	snapshot map[string]fileStamp
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// routeTable is a version of the definitions read from the files
+type routeTable struct {
+	defs    []core.RouteDefinition
+	version uint64
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatchingLoader reads the definitions of paths and starts watching them for changes
+// every interval. A non positive interval uses DefaultWatchInterval.
+// Close must be called to stop watching.
+func NewWatchingLoader(interval time.Duration, paths ...string) (*WatchingLoader, error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	l := &WatchingLoader{
+		paths:    paths,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	snapshot, err := l.stamps()
+	if err != nil {
+		return nil, err
+	}
+	defs, err := ReadDefinitions(paths...)
+	if err != nil {
+		return nil, err
+	}
+	l.snapshot = snapshot
+	l.table.Store(&routeTable{defs: defs, version: 1})
+	go l.watch()
+	return l, nil
+}
+
+// Ensures WatchingLoader implements core.VersionedLoader
+var (
+	_ core.VersionedLoader = (*WatchingLoader)(nil)
+)
+
+func (l *WatchingLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	return l.Definitions(), nil
+}
+
+// LoadVersion returns the current route table, whose version changes on every reload
+func (l *WatchingLoader) LoadVersion(r *http.Request) ([]core.RouteDefinition, uint64, error) {
+	table := l.table.Load()
+	return table.defs, table.version, nil
+}
+
+// Definitions returns the current route table
+func (l *WatchingLoader) Definitions() []core.RouteDefinition {
+	return l.table.Load().defs
+}
+
+// Close stops watching for changes
+func (l *WatchingLoader) Close() error {
+	l.stopOnce.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *WatchingLoader) watch() {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			l.Reload()
+		}
+	}
+}
+
+// Reload re-reads the definitions when any file changed since the last check.
+// It returns true when the route table was replaced.
+func (l *WatchingLoader) Reload() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	snapshot, err := l.stamps()
+	if err != nil {
+		log.Printf("Error while checking route definition files: %s", err)
+		return false
+	}
+	if sameStamps(l.snapshot, snapshot) {
+		return false
+	}
+	l.snapshot = snapshot
+
+	defs, err := ReadDefinitions(l.paths...)
+	if err != nil {
+		log.Printf("Keeping previous route definitions, reload failed: %s", err)
+		return false
+	}
+	l.table.Store(&routeTable{defs: defs, version: l.table.Load().version + 1})
+	log.Printf("Reloaded %d route definition(s)", len(defs))
+	return true
+}
+
+func (l *WatchingLoader) stamps() (map[string]fileStamp, error) {
+	files, err := definitionFiles(l.paths...)
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[string]fileStamp, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		stamps[filepath.Clean(file)] = fileStamp{info.ModTime(), info.Size()}
+	}
+	return stamps, nil
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, stamp := range a {
+		other, ok := b[file]
+		if !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
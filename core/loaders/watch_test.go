@@ -0,0 +1,70 @@
+package loaders
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchingLoader(t *testing.T) {
+	t.Run("should swap definitions when files change", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "items.yaml", yamlDefinitions)
+		l, err := NewWatchingLoader(time.Hour, dir)
+		assert.Nil(t, err)
+		defer l.Close()
+		assert.Len(t, l.Definitions(), 1)
+		assert.False(t, l.Reload())
+
+		writeFile(t, dir, "health.json", jsonDefinitions)
+		assert.True(t, l.Reload())
+		defs, err := l.Load(httptest.NewRequest("GET", "/health", nil))
+		assert.Nil(t, err)
+		assert.Len(t, defs, 2)
+
+		assert.Nil(t, os.Remove(filepath.Join(dir, "items.yaml")))
+		assert.True(t, l.Reload())
+		assert.Len(t, l.Definitions(), 1)
+	})
+
+	t.Run("should keep previous definitions when reload fails", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeFile(t, dir, "items.yaml", yamlDefinitions)
+		l, err := NewWatchingLoader(time.Hour, dir)
+		assert.Nil(t, err)
+		defer l.Close()
+
+		writeFile(t, dir, "items.yaml", "routes:\n  - path: items\n    method: GET\n    response:\n      type: STATIC\n")
+		assert.False(t, l.Reload())
+		assert.Equal(t, "/items/{id}", l.Definitions()[0].Path)
+
+		os.Remove(path)
+		writeFile(t, dir, "items.yaml", "routes:\n  - path: /items\n    method: GET\n    response:\n      type: STATIC\n")
+		assert.True(t, l.Reload())
+		assert.Equal(t, "/items", l.Definitions()[0].Path)
+	})
+
+	t.Run("should pick up changes in the background", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "items.yaml", yamlDefinitions)
+		l, err := NewWatchingLoader(10*time.Millisecond, dir)
+		assert.Nil(t, err)
+		defer l.Close()
+
+		writeFile(t, dir, "health.json", jsonDefinitions)
+		assert.Eventually(t, func() bool {
+			return len(l.Definitions()) == 2
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should fail when initial definitions are invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "items.yaml", "routes:\n  - path: /items\n")
+		_, err := NewWatchingLoader(time.Hour, dir)
+		assert.ErrorIs(t, err, ErrMissingPathOrMethod)
+	})
+}
@@ -0,0 +1,124 @@
+package loaders
+
+import "github.com/bmviniciuss/forger/core"
+
+// FileWebSocketScript mirrors core.WebSocketScript
+type FileWebSocketScript struct {
+	OnConnect []FileWebSocketMessage `json:"on_connect,omitempty" yaml:"on_connect,omitempty"`
+	Replies   []FileWebSocketReply   `json:"replies,omitempty" yaml:"replies,omitempty"`
+	Pushes    []FileWebSocketPush    `json:"pushes,omitempty" yaml:"pushes,omitempty"`
+}
+
+// FileWebSocketMessage mirrors core.WebSocketMessage. Like response bodies,
+// Data may be a string or any structured value, which is encoded as JSON.
+type FileWebSocketMessage struct {
+	Data  interface{} `json:"data" yaml:"data"`
+	Delay string      `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+type FileWebSocketReply struct {
+	Matchers []FileMessageMatcher   `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Messages []FileWebSocketMessage `json:"messages" yaml:"messages"`
+}
+
+type FileWebSocketPush struct {
+	Data     interface{} `json:"data" yaml:"data"`
+	After    string      `json:"after,omitempty" yaml:"after,omitempty"`
+	Interval string      `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// FileMessageMatcher mirrors core.MessageMatcher
+type FileMessageMatcher struct {
+	Key      string `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Matcher converts the file matcher, shared by WebSocket replies and gRPC stubs
+func (f FileMessageMatcher) Matcher() (*core.MessageMatcher, error) {
+	operator, err := core.NewMatchOperator(f.Operator)
+	if err != nil {
+		return nil, err
+	}
+	return &core.MessageMatcher{Key: f.Key, Operator: operator, Value: f.Value}, nil
+}
+
+// NewFileWebSocketScript creates the file representation of a WebSocket script
+func NewFileWebSocketScript(s core.WebSocketScript) FileWebSocketScript {
+	f := FileWebSocketScript{OnConnect: newFileWebSocketMessages(s.OnConnect)}
+	for _, reply := range s.Replies {
+		fr := FileWebSocketReply{Messages: newFileWebSocketMessages(reply.Messages)}
+		for _, m := range reply.Matchers {
+			fr.Matchers = append(fr.Matchers, FileMessageMatcher{Key: m.Key, Operator: m.Operator.String(), Value: m.Value})
+		}
+		f.Replies = append(f.Replies, fr)
+	}
+	for _, p := range s.Pushes {
+		f.Pushes = append(f.Pushes, FileWebSocketPush{
+			Data:     p.Data,
+			After:    durationString(p.After),
+			Interval: durationString(p.Interval),
+		})
+	}
+	return f
+}
+
+func newFileWebSocketMessages(messages []core.WebSocketMessage) []FileWebSocketMessage {
+	var fm []FileWebSocketMessage
+	for _, m := range messages {
+		fm = append(fm, FileWebSocketMessage{Data: m.Data, Delay: durationString(m.Delay)})
+	}
+	return fm
+}
+
+func (f FileWebSocketScript) Script() (*core.WebSocketScript, error) {
+	s := &core.WebSocketScript{}
+	var err error
+	if s.OnConnect, err = webSocketMessages(f.OnConnect); err != nil {
+		return nil, err
+	}
+	for _, fr := range f.Replies {
+		reply := core.WebSocketReply{}
+		if reply.Messages, err = webSocketMessages(fr.Messages); err != nil {
+			return nil, err
+		}
+		for _, fm := range fr.Matchers {
+			matcher, err := fm.Matcher()
+			if err != nil {
+				return nil, err
+			}
+			reply.Matchers = append(reply.Matchers, *matcher)
+		}
+		s.Replies = append(s.Replies, reply)
+	}
+	for _, fp := range f.Pushes {
+		push := core.WebSocketPush{}
+		if push.Data, err = EncodeBody(fp.Data); err != nil {
+			return nil, err
+		}
+		if push.After, err = ParseDuration(fp.After); err != nil {
+			return nil, err
+		}
+		if push.Interval, err = ParseDuration(fp.Interval); err != nil {
+			return nil, err
+		}
+		s.Pushes = append(s.Pushes, push)
+	}
+	return s, nil
+}
+
+func webSocketMessages(fm []FileWebSocketMessage) ([]core.WebSocketMessage, error) {
+	var messages []core.WebSocketMessage
+	for _, f := range fm {
+		data, err := EncodeBody(f.Data)
+		if err != nil {
+			return nil, err
+		}
+		delay, err := ParseDuration(f.Delay)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, core.WebSocketMessage{Data: data, Delay: delay})
+	}
+	return messages, nil
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+type MatcherSource string
+
+const (
+	MATCHER_SOURCE_HEADER MatcherSource = "HEADER"
+	MATCHER_SOURCE_QUERY  MatcherSource = "QUERY"
+	MATCHER_SOURCE_COOKIE MatcherSource = "COOKIE"
+	MATCHER_SOURCE_BODY   MatcherSource = "BODY"
+	// MATCHER_SOURCE_GRAPHQL_OPERATION matches the GraphQL operation name, ignoring Key
+	MATCHER_SOURCE_GRAPHQL_OPERATION MatcherSource = "GRAPHQL_OPERATION"
+	// MATCHER_SOURCE_GRAPHQL_VARIABLE matches the GraphQL variable at the Key gjson path
+	MATCHER_SOURCE_GRAPHQL_VARIABLE MatcherSource = "GRAPHQL_VARIABLE"
+)
+
+func NewMatcherSource(s string) (MatcherSource, error) {
+	switch s {
+	case MATCHER_SOURCE_HEADER.String():
+		return MATCHER_SOURCE_HEADER, nil
+	case MATCHER_SOURCE_QUERY.String():
+		return MATCHER_SOURCE_QUERY, nil
+	case MATCHER_SOURCE_COOKIE.String():
+		return MATCHER_SOURCE_COOKIE, nil
+	case MATCHER_SOURCE_BODY.String():
+		return MATCHER_SOURCE_BODY, nil
+	case MATCHER_SOURCE_GRAPHQL_OPERATION.String():
+		return MATCHER_SOURCE_GRAPHQL_OPERATION, nil
+	case MATCHER_SOURCE_GRAPHQL_VARIABLE.String():
+		return MATCHER_SOURCE_GRAPHQL_VARIABLE, nil
+	default:
+		return "", ErrInvalidMatcherSource
+	}
+}
+
+func (ms MatcherSource) String() string {
+	return string(ms)
+}
+
+type MatchOperator string
+
+const (
+	MATCH_OPERATOR_EQUALS   MatchOperator = "EQUALS"
+	MATCH_OPERATOR_CONTAINS MatchOperator = "CONTAINS"
+	MATCH_OPERATOR_REGEX    MatchOperator = "REGEX"
+	MATCH_OPERATOR_EXISTS   MatchOperator = "EXISTS"
+)
+
+func NewMatchOperator(o string) (MatchOperator, error) {
+	switch o {
+	case MATCH_OPERATOR_EQUALS.String():
+		return MATCH_OPERATOR_EQUALS, nil
+	case MATCH_OPERATOR_CONTAINS.String():
+		return MATCH_OPERATOR_CONTAINS, nil
+	case MATCH_OPERATOR_REGEX.String():
+		return MATCH_OPERATOR_REGEX, nil
+	case MATCH_OPERATOR_EXISTS.String():
+		return MATCH_OPERATOR_EXISTS, nil
+	default:
+		return "", ErrInvalidMatchOperator
+	}
+}
+
+func (mo MatchOperator) String() string {
+	return string(mo)
+}
+
+// RequestMatcher is a condition that an incoming request must satisfy for a
+// RouteDefinition to be selected.
+// Key is the header name, query parameter, cookie name or, for BODY and
+// GRAPHQL_VARIABLE matchers, a gjson path into the request body or variables.
+type RequestMatcher struct {
+	Source   MatcherSource
+	Key      string
+	Operator MatchOperator
+	Value    string
+
+	regex *regexp.Regexp
+}
+
+func NewRequestMatcher(source MatcherSource, key string, operator MatchOperator, value string) *RequestMatcher {
+	return &RequestMatcher{
+		Source:   source,
+		Key:      key,
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+// Matches reports whether the request satisfies the matcher.
+// BODY matchers read the request body and restore it so it can be read again
+// when the response is built.
+func (m RequestMatcher) Matches(r *http.Request) (bool, error) {
+	value, found, err := m.lookup(r)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	return matchValue(m.Operator, m.Value, value, m.regex)
+}
+
+// Compile parses the pattern of REGEX matchers once, so it is not parsed
+// again on each request and bad patterns are reported up front
+func (m *RequestMatcher) Compile() error {
+	if m.Operator != MATCH_OPERATOR_REGEX {
+		return nil
+	}
+	regex, err := regexp.Compile(m.Value)
+	if err != nil {
+		return err
+	}
+	m.regex = regex
+	return nil
+}
+
+// matchValue compares a value found in a request against the expected one,
+// using regex for REGEX matchers when their pattern was already compiled
+func matchValue(operator MatchOperator, expected, value string, regex *regexp.Regexp) (bool, error) {
+	switch operator {
+	case MATCH_OPERATOR_EXISTS:
+		return true, nil
+	case MATCH_OPERATOR_EQUALS:
+		return value == expected, nil
+	case MATCH_OPERATOR_CONTAINS:
+		return strings.Contains(value, expected), nil
+	case MATCH_OPERATOR_REGEX:
+		if regex != nil {
+			return regex.MatchString(value), nil
+		}
+		return regexp.MatchString(expected, value)
+	default:
+		return false, ErrInvalidMatchOperator
+	}
+}
+
+func (m RequestMatcher) lookup(r *http.Request) (string, bool, error) {
+	switch m.Source {
+	case MATCHER_SOURCE_HEADER:
+		values, ok := r.Header[http.CanonicalHeaderKey(m.Key)]
+		if !ok || len(values) == 0 {
+			return "", false, nil
+		}
+		return values[0], true, nil
+	case MATCHER_SOURCE_QUERY:
+		values, ok := r.URL.Query()[m.Key]
+		if !ok || len(values) == 0 {
+			return "", false, nil
+		}
+		return values[0], true, nil
+	case MATCHER_SOURCE_COOKIE:
+		cookie, err := r.Cookie(m.Key)
+		if err != nil {
+			return "", false, nil
+		}
+		return cookie.Value, true, nil
+	case MATCHER_SOURCE_BODY:
+		body, err := PeekBody(r)
+		if err != nil {
+			return "", false, err
+		}
+		res := gjson.Get(body, m.Key)
+		if !res.Exists() {
+			return "", false, nil
+		}
+		return res.String(), true, nil
+	case MATCHER_SOURCE_GRAPHQL_OPERATION, MATCHER_SOURCE_GRAPHQL_VARIABLE:
+		body, err := PeekBody(r)
+		if err != nil {
+			return "", false, err
+		}
+		g := ParseGraphQLRequest(r, body)
+		if m.Source == MATCHER_SOURCE_GRAPHQL_OPERATION {
+			return g.Operation(), true, nil
+		}
+		res := gjson.GetBytes(g.Variables, m.Key)
+		if !res.Exists() {
+			return "", false, nil
+		}
+		return res.String(), true, nil
+	default:
+		return "", false, ErrInvalidMatcherSource
+	}
+}
+
+// PeekBody reads the request body and replaces it with an in-memory copy,
+// so it can be read again. What could be read is kept even when reading fails.
+func PeekBody(r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+	raw, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+	return string(raw), err
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// proxyClient does not follow redirects so they are returned to the client as they are
+var proxyClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// hopHeaders are meaningful only for a single connection and are not forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"Content-Length",
+}
+
+// buildProxyResponse forwards the request to the upstream and returns its response.
+// Headers defined in the route response are applied over the upstream ones.
+func (rr RouteResponse) buildProxyResponse(r *http.Request, reqBody *string) (Result, error) {
+	headers, err := rr.buildHeaders(r, reqBody)
+	if err != nil {
+		return Result{}, err
+	}
+	result, err := Forward(r, rr.Upstream, *reqBody)
+	if err != nil {
+		return Result{}, err
+	}
+	for name, value := range headers {
+		result.Headers[name] = value
+	}
+	return result, nil
+}
+
+// Forward sends the request, with the given body, to the same path and query of the upstream base URL.
+// Only the first value of each upstream response header is kept.
+func Forward(r *http.Request, upstream string, body string) (Result, error) {
+	target, err := UpstreamURL(upstream, r.URL)
+	if err != nil {
+		return Result{}, err
+	}
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target.String(), strings.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header = r.Header.Clone()
+	removeHopHeaders(req.Header)
+
+	res, err := proxyClient.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Result{}, err
+	}
+
+	removeHopHeaders(res.Header)
+	headers := make(map[string]string, len(res.Header))
+	for name := range res.Header {
+		headers[name] = res.Header.Get(name)
+	}
+	resBody := string(raw)
+	return Result{
+		StatusCode: res.StatusCode,
+		Body:       &resBody,
+		Headers:    headers,
+	}, nil
+}
+
+// UpstreamURL joins the path and query of u to the upstream base URL
+func UpstreamURL(upstream string, u *url.URL) (*url.URL, error) {
+	base, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, ErrInvalidUpstream
+	}
+	target := *base
+	target.Path = strings.TrimSuffix(base.Path, "/") + u.Path
+	target.RawPath = ""
+	target.RawQuery = u.RawQuery
+	return &target, nil
+}
+
+func removeHopHeaders(h http.Header) {
+	for _, name := range hopHeaders {
+		h.Del(name)
+	}
+}
@@ -0,0 +1,26 @@
+package core
+
+type ResponseMode string
+
+const (
+	RESPONSE_MODE_SEQUENTIAL ResponseMode = "SEQUENTIAL"
+	RESPONSE_MODE_CYCLIC     ResponseMode = "CYCLIC"
+	RESPONSE_MODE_RANDOM     ResponseMode = "RANDOM"
+)
+
+func NewResponseMode(m string) (ResponseMode, error) {
+	switch m {
+	case RESPONSE_MODE_SEQUENTIAL.String():
+		return RESPONSE_MODE_SEQUENTIAL, nil
+	case RESPONSE_MODE_CYCLIC.String():
+		return RESPONSE_MODE_CYCLIC, nil
+	case RESPONSE_MODE_RANDOM.String():
+		return RESPONSE_MODE_RANDOM, nil
+	default:
+		return "", ErrInvalidResponseMode
+	}
+}
+
+func (rm ResponseMode) String() string {
+	return string(rm)
+}
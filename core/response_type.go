@@ -3,8 +3,12 @@ package core
 type RouteResponseType string
 
 const (
-	RESPONSE_TYPE_STATIC  RouteResponseType = "STATIC"
-	RESPONSE_TYPE_DYNAMIC RouteResponseType = "DYNAMIC"
+	RESPONSE_TYPE_STATIC    RouteResponseType = "STATIC"
+	RESPONSE_TYPE_DYNAMIC   RouteResponseType = "DYNAMIC"
+	RESPONSE_TYPE_PROXY     RouteResponseType = "PROXY"
+	RESPONSE_TYPE_SSE       RouteResponseType = "SSE"
+	RESPONSE_TYPE_WEBSOCKET RouteResponseType = "WEBSOCKET"
+	RESPONSE_TYPE_GRAPHQL   RouteResponseType = "GRAPHQL"
 )
 
 func NewRouteResponseType(t string) (RouteResponseType, error) {
@@ -13,6 +17,14 @@ func NewRouteResponseType(t string) (RouteResponseType, error) {
 		return RESPONSE_TYPE_STATIC, nil
 	case RESPONSE_TYPE_DYNAMIC.String():
 		return RESPONSE_TYPE_DYNAMIC, nil
+	case RESPONSE_TYPE_PROXY.String():
+		return RESPONSE_TYPE_PROXY, nil
+	case RESPONSE_TYPE_SSE.String():
+		return RESPONSE_TYPE_SSE, nil
+	case RESPONSE_TYPE_WEBSOCKET.String():
+		return RESPONSE_TYPE_WEBSOCKET, nil
+	case RESPONSE_TYPE_GRAPHQL.String():
+		return RESPONSE_TYPE_GRAPHQL, nil
 	default:
 		return "", ErrInvalidRouteResponseType
 	}
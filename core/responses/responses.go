@@ -6,9 +6,17 @@ type Response struct {
 }
 
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Reason  string `json:"reason,omitempty"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+	Reason  string        `json:"reason,omitempty"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail points at the part of a request an error is about
+type ErrorDetail struct {
+	In     string `json:"in"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
 }
 
 func NewNotFoundResponse() *Response {
@@ -31,3 +39,48 @@ func NewInternalErrorResponse(message, reason string) *Response {
 		},
 	}
 }
+
+func NewBadRequestResponse(message, reason string) *Response {
+	return &Response{
+		StatusCode: 400,
+		Error: ErrorResponse{
+			Code:    "bad_request",
+			Message: message,
+			Reason:  reason,
+		},
+	}
+}
+
+func NewVerificationFailedResponse(reason string) *Response {
+	return &Response{
+		StatusCode: 422,
+		Error: ErrorResponse{
+			Code:    "verification_failed",
+			Message: "Request verification failed",
+			Reason:  reason,
+		},
+	}
+}
+
+func NewBadGatewayResponse(message, reason string) *Response {
+	return &Response{
+		StatusCode: 502,
+		Error: ErrorResponse{
+			Code:    "bad_gateway",
+			Message: message,
+			Reason:  reason,
+		},
+	}
+}
+
+func NewInvalidRequestResponse(reason string, details []ErrorDetail) *Response {
+	return &Response{
+		StatusCode: 400,
+		Error: ErrorResponse{
+			Code:    "invalid_request",
+			Message: "Request does not conform to the API specification",
+			Reason:  reason,
+			Details: details,
+		},
+	}
+}
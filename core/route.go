@@ -1,11 +1,199 @@
 package core
 
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
 type RouteDefinition struct {
 	Path     string
 	Method   string
+	Matchers []RequestMatcher
 	Response RouteResponse
+	// Responses, when not empty, replaces Response with an ordered list of
+	// responses picked according to ResponseMode
+	Responses    []RouteResponse
+	ResponseMode ResponseMode
+	// Scenario names the state machine this definition takes part in.
+	// When RequiredState is set the definition only matches while the scenario
+	// is in that state, and NewState, when set, is applied after responding.
+	Scenario      string
+	RequiredState string
+	NewState      string
+	// Seed, when set, makes the random values of the responses reproducible.
+	// It may be a template, like {{ requestVar "id" }}, so each request gets
+	// its own seed while the same request always renders the same values.
+	Seed string
+
+	seed *Template
 }
 
 func NewRouteDefinition(path, method string, response RouteResponse) *RouteDefinition {
-	return &RouteDefinition{path, method, response}
+	return &RouteDefinition{
+		Path:     path,
+		Method:   method,
+		Response: response,
+	}
+}
+
+// Matches reports whether the request satisfies every matcher of the definition.
+// A definition without matchers matches any request routed to its path and method.
+func (rd RouteDefinition) Matches(r *http.Request) (bool, error) {
+	for _, m := range rd.Matchers {
+		ok, err := m.Matches(r)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// SelectResponse returns the response for the given zero based call number.
+// SEQUENTIAL (the default) keeps returning the last response once the list is exhausted,
+// CYCLIC starts over from the first one and RANDOM ignores the call number.
+func (rd RouteDefinition) SelectResponse(call uint64) RouteResponse {
+	total := uint64(len(rd.Responses))
+	if total == 0 {
+		return rd.Response
+	}
+	switch rd.ResponseMode {
+	case RESPONSE_MODE_CYCLIC:
+		return rd.Responses[call%total]
+	case RESPONSE_MODE_RANDOM:
+		return rd.Responses[rand.Intn(int(total))]
+	default:
+		if call >= total {
+			return rd.Responses[total-1]
+		}
+		return rd.Responses[call]
+	}
+}
+
+// Compile parses the templates of every response of the definition.
+// Responses and Matchers are copied before compiling so the caller's slices are left untouched.
+func (rd *RouteDefinition) Compile() error {
+	if err := rd.Response.Compile(); err != nil {
+		return err
+	}
+	if len(rd.Matchers) > 0 {
+		matchers := make([]RequestMatcher, len(rd.Matchers))
+		copy(matchers, rd.Matchers)
+		for i := range matchers {
+			if err := matchers[i].Compile(); err != nil {
+				return err
+			}
+		}
+		rd.Matchers = matchers
+	}
+	if isTemplate(rd.Seed) {
+		seed, err := NewTemplate(rd.Seed)
+		if err != nil {
+			return err
+		}
+		rd.seed = seed
+	}
+	if len(rd.Responses) == 0 {
+		return nil
+	}
+	responses := make([]RouteResponse, len(rd.Responses))
+	copy(responses, rd.Responses)
+	for i := range responses {
+		if err := responses[i].Compile(); err != nil {
+			return err
+		}
+	}
+	rd.Responses = responses
+	return nil
+}
+
+// Validate checks that the definition can be registered and served
+func (rd RouteDefinition) Validate() error {
+	if !strings.HasPrefix(rd.Path, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidRoutePath, rd.Path)
+	}
+	if rd.Method == "" {
+		return ErrInvalidRouteMethod
+	}
+	responses := rd.Responses
+	if len(responses) == 0 {
+		responses = []RouteResponse{rd.Response}
+	}
+	for _, res := range responses {
+		if _, err := NewRouteResponseType(res.Type.String()); err != nil {
+			return err
+		}
+		if res.StatusCode != 0 && (res.StatusCode < 100 || res.StatusCode > 999) {
+			return fmt.Errorf("%w: %d", ErrInvalidStatusCode, res.StatusCode)
+		}
+		if err := res.Compile(); err != nil {
+			return err
+		}
+		if err := validateFaults(res.Faults); err != nil {
+			return err
+		}
+		if err := validateEvents(res); err != nil {
+			return err
+		}
+		if res.Type == RESPONSE_TYPE_WEBSOCKET {
+			if res.WebSocket == nil {
+				return ErrInvalidWebSocketScript
+			}
+			if err := res.WebSocket.Validate(); err != nil {
+				return err
+			}
+		}
+		if res.DelayProfile != nil {
+			if err := res.DelayProfile.Validate(); err != nil {
+				return err
+			}
+		}
+		if res.Throttle != nil {
+			if err := res.Throttle.Validate(); err != nil {
+				return err
+			}
+		}
+		if res.Type == RESPONSE_TYPE_PROXY {
+			if _, err := UpstreamURL(res.Upstream, &url.URL{}); err != nil {
+				return err
+			}
+		}
+	}
+	if isTemplate(rd.Seed) {
+		if _, err := NewTemplate(rd.Seed); err != nil {
+			return err
+		}
+	}
+	if rd.ResponseMode != "" {
+		if _, err := NewResponseMode(rd.ResponseMode.String()); err != nil {
+			return err
+		}
+	}
+	for _, m := range rd.Matchers {
+		if _, err := NewMatcherSource(m.Source.String()); err != nil {
+			return err
+		}
+		if _, err := NewMatchOperator(m.Operator.String()); err != nil {
+			return err
+		}
+		if err := m.Compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateDefinitions validates every definition, reporting the first invalid one
+func ValidateDefinitions(defs []RouteDefinition) error {
+	for i, def := range defs {
+		if err := def.Validate(); err != nil {
+			return fmt.Errorf("route %d (%s %s): %w", i, def.Method, def.Path, err)
+		}
+	}
+	return nil
 }
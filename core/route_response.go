@@ -3,6 +3,7 @@ package core
 import (
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,12 +14,37 @@ type RouteResponse struct {
 	Body       string
 	Headers    map[string]string
 	Delay      time.Duration
+	// DelayProfile, when set, samples the delay of each response instead of using Delay
+	DelayProfile *DelayProfile
+	// Faults, picked according to their chances, replace the response with a misbehaving one
+	Faults []Fault
+	// Throttle, when set, slows down and chunks the writing of the body
+	Throttle *Throttle
+	// Upstream is the base URL requests are forwarded to by PROXY responses
+	Upstream string
+	// Events are streamed by SSE responses, Repeat more times after the first,
+	// or until the client goes away when it is REPEAT_FOREVER
+	Events []Event
+	Repeat int
+	// WebSocket is the conversation WEBSOCKET responses run
+	WebSocket *WebSocketScript
+
+	templates *responseTemplates
+}
+
+// responseTemplates holds the templates parsed by Compile
+type responseTemplates struct {
+	body    *Template
+	headers map[string]*Template
+	events  []*Template
 }
 
 type Result struct {
 	StatusCode int
 	Body       *string
 	Headers    map[string]string
+	// Events holds the rendered events of SSE responses
+	Events []Event
 }
 
 func NewRouteResponse(t RouteResponseType, statusCode int, body string, headers map[string]string, delay time.Duration) *RouteResponse {
@@ -31,13 +57,84 @@ func NewRouteResponse(t RouteResponseType, statusCode int, body string, headers
 	}
 }
 
+// Compile parses the body of DYNAMIC and GRAPHQL responses, every templated header,
+// SSE event data and WebSocket script once, so they are not parsed again on each request.
+func (rr *RouteResponse) Compile() error {
+	templates := &responseTemplates{headers: make(map[string]*Template)}
+	if rr.Type == RESPONSE_TYPE_DYNAMIC || rr.Type == RESPONSE_TYPE_GRAPHQL {
+		body, err := NewTemplate(rr.Body)
+		if err != nil {
+			return err
+		}
+		templates.body = body
+	}
+	for name, value := range rr.Headers {
+		if !isTemplate(value) {
+			continue
+		}
+		header, err := NewTemplate(value)
+		if err != nil {
+			return err
+		}
+		templates.headers[name] = header
+	}
+	templates.events = make([]*Template, len(rr.Events))
+	for i, e := range rr.Events {
+		if !isTemplate(e.Data) {
+			continue
+		}
+		data, err := NewTemplate(e.Data)
+		if err != nil {
+			return err
+		}
+		templates.events[i] = data
+	}
+	if rr.WebSocket != nil {
+		script, err := rr.WebSocket.Compile()
+		if err != nil {
+			return err
+		}
+		rr.WebSocket = script
+	}
+	rr.templates = templates
+	return nil
+}
+
+// SampleDelay returns how long the response should be delayed, using fallback
+// when neither a delay profile nor a fixed delay is set for the response
+func (rr RouteResponse) SampleDelay(fallback *DelayProfile) time.Duration {
+	switch {
+	case rr.DelayProfile != nil:
+		return rr.DelayProfile.Sample()
+	case rr.Delay > 0:
+		return rr.Delay
+	case fallback != nil:
+		return fallback.Sample()
+	default:
+		return 0
+	}
+}
+
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
 func (rr RouteResponse) BuildResponse(r *http.Request) (Result, error) {
 	reqBody, err := readBody(r)
 	if err != nil {
 		return Result{}, err
 	}
+	if rr.Type == RESPONSE_TYPE_PROXY {
+		return rr.buildProxyResponse(r, &reqBody)
+	}
 
-	body, err := rr.buildResponseBody(r, &reqBody)
+	var events []Event
+	var body *string
+	if rr.Type == RESPONSE_TYPE_SSE {
+		events, body, err = rr.buildEvents(r, &reqBody)
+	} else {
+		body, err = rr.buildResponseBody(r, &reqBody)
+	}
 	if err != nil {
 		return Result{}, err
 	}
@@ -49,6 +146,7 @@ func (rr RouteResponse) BuildResponse(r *http.Request) (Result, error) {
 		StatusCode: rr.buildResponseStatusCode(),
 		Body:       body,
 		Headers:    headers,
+		Events:     events,
 	}, nil
 }
 
@@ -69,7 +167,23 @@ func (rr RouteResponse) buildResponseBody(r *http.Request, reqBody *string) (*st
 	case RESPONSE_TYPE_STATIC:
 		return &rr.Body, nil
 	case RESPONSE_TYPE_DYNAMIC:
+		if rr.templates != nil && rr.templates.body != nil {
+			return rr.templates.body.Execute(r, reqBody)
+		}
 		return processString(r, rr.Body, reqBody)
+	case RESPONSE_TYPE_GRAPHQL:
+		var body *string
+		var err error
+		if rr.templates != nil && rr.templates.body != nil {
+			body, err = rr.templates.body.Execute(r, reqBody)
+		} else {
+			body, err = processString(r, rr.Body, reqBody)
+		}
+		if err != nil {
+			return nil, err
+		}
+		data := wrapGraphQLData(*body)
+		return &data, nil
 	default:
 		return nil, ErrResponseNotImplemented
 	}
@@ -77,27 +191,50 @@ func (rr RouteResponse) buildResponseBody(r *http.Request, reqBody *string) (*st
 
 func (rr RouteResponse) buildResponseStatusCode() int {
 	switch rr.Type {
-	case RESPONSE_TYPE_STATIC:
-		return rr.StatusCode
-	case RESPONSE_TYPE_DYNAMIC:
+	case RESPONSE_TYPE_STATIC, RESPONSE_TYPE_DYNAMIC, RESPONSE_TYPE_SSE, RESPONSE_TYPE_GRAPHQL:
+		if rr.StatusCode == 0 {
+			return http.StatusOK
+		}
 		return rr.StatusCode
 	default:
 		return http.StatusNotImplemented
 	}
 }
 
+// buildHeaders renders the headers in the order of their names, so seeded
+// requests draw the same random values for the same headers
 func (rr RouteResponse) buildHeaders(r *http.Request, reqBody *string) (map[string]string, error) {
+	names := make([]string, 0, len(rr.Headers))
+	for name := range rr.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 	headers := make(map[string]string)
-	for name, value := range rr.Headers {
-		if strings.Contains(value, "{{") {
-			val, err := processString(r, value, reqBody)
-			if err != nil {
-				return nil, err
-			}
-			headers[name] = *val
-		} else {
+	for _, name := range names {
+		value := rr.Headers[name]
+		if !isTemplate(value) {
 			headers[name] = value
+			continue
+		}
+		var val *string
+		var err error
+		if t, ok := rr.compiledHeader(name); ok {
+			val, err = t.Execute(r, reqBody)
+		} else {
+			val, err = processString(r, value, reqBody)
+		}
+		if err != nil {
+			return nil, err
 		}
+		headers[name] = *val
 	}
 	return headers, nil
 }
+
+func (rr RouteResponse) compiledHeader(name string) (*Template, bool) {
+	if rr.templates == nil {
+		return nil, false
+	}
+	t, ok := rr.templates.headers[name]
+	return t, ok
+}
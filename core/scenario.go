@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// SCENARIO_STATE_STARTED is the state every scenario is in before any route moves it
+const SCENARIO_STATE_STARTED = "STARTED"
+
+// ScenarioStore keeps the current state of each named scenario.
+// Implementations must be safe for concurrent use.
+type ScenarioStore interface {
+	GetState(ctx context.Context, scenario string) (string, error)
+	SetState(ctx context.Context, scenario, state string) error
+	Reset(ctx context.Context) error
+}
+
+type InMemoryScenarioStore struct {
+	mu     sync.RWMutex
+	states map[string]string
+}
+
+func NewInMemoryScenarioStore() *InMemoryScenarioStore {
+	return &InMemoryScenarioStore{states: make(map[string]string)}
+}
+
+// Ensures InMemoryScenarioStore implements ScenarioStore
+var (
+	_ ScenarioStore = (*InMemoryScenarioStore)(nil)
+)
+
+func (s *InMemoryScenarioStore) GetState(ctx context.Context, scenario string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.states[scenario]
+	if !ok {
+		return SCENARIO_STATE_STARTED, nil
+	}
+	return state, nil
+}
+
+func (s *InMemoryScenarioStore) SetState(ctx context.Context, scenario, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[scenario] = state
+	return nil
+}
+
+func (s *InMemoryScenarioStore) Reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = make(map[string]string)
+	return nil
+}
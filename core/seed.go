@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core/generators"
+)
+
+// SeedRequest returns the request with the random values of its responses
+// drawn from a seeded source, see generators.WithSeed. The seed of the
+// definition is rendered for the request and combined with the global seed,
+// when there is one. Requests are returned untouched when neither is set.
+func (rd RouteDefinition) SeedRequest(r *http.Request, body string, global *int64) (*http.Request, error) {
+	if rd.Seed == "" {
+		if global == nil {
+			return r, nil
+		}
+		return r.WithContext(generators.WithSeed(r.Context(), *global)), nil
+	}
+	value := rd.Seed
+	if isTemplate(value) {
+		var rendered *string
+		var err error
+		if rd.seed != nil {
+			rendered, err = rd.seed.Execute(r, &body)
+		} else {
+			rendered, err = processString(r, value, &body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		value = *rendered
+	}
+	if global != nil {
+		value = fmt.Sprintf("%d:%s", *global, value)
+	}
+	seed, err := generators.ParseSeed(value)
+	if err != nil {
+		return nil, err
+	}
+	return r.WithContext(generators.WithSeed(r.Context(), seed)), nil
+}
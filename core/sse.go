@@ -0,0 +1,97 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// REPEAT_FOREVER streams the events of SSE responses until the client goes away
+const REPEAT_FOREVER = -1
+
+// Event is a Server-Sent Event streamed by SSE responses.
+// Data may be a template, rendered once per request.
+// Delay is how long to wait before sending the event.
+type Event struct {
+	Name  string
+	ID    string
+	Data  string
+	Delay time.Duration
+}
+
+// Encode returns the event in the text/event-stream format
+func (e Event) Encode() string {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Name != "" {
+		b.WriteString("event: " + e.Name + "\n")
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (e Event) Validate() error {
+	if e.Delay < 0 {
+		return ErrInvalidEvent
+	}
+	return nil
+}
+
+func validateEvents(rr RouteResponse) error {
+	if rr.Type != RESPONSE_TYPE_SSE {
+		return nil
+	}
+	if len(rr.Events) == 0 || rr.Repeat < REPEAT_FOREVER {
+		return ErrInvalidEvent
+	}
+	var total time.Duration
+	for _, e := range rr.Events {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		total += e.Delay
+	}
+	// without any delay an endless stream would spin as fast as it can write
+	if rr.Repeat == REPEAT_FOREVER && total == 0 {
+		return ErrInvalidEvent
+	}
+	return nil
+}
+
+// buildEvents renders the data of the response events.
+// The result body holds every event once, encoded as an event stream.
+func (rr RouteResponse) buildEvents(r *http.Request, reqBody *string) ([]Event, *string, error) {
+	events := make([]Event, len(rr.Events))
+	var body strings.Builder
+	for i, e := range rr.Events {
+		if isTemplate(e.Data) {
+			var data *string
+			var err error
+			if t, ok := rr.compiledEvent(i); ok {
+				data, err = t.Execute(r, reqBody)
+			} else {
+				data, err = processString(r, e.Data, reqBody)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			e.Data = *data
+		}
+		events[i] = e
+		body.WriteString(e.Encode())
+	}
+	encoded := body.String()
+	return events, &encoded, nil
+}
+
+func (rr RouteResponse) compiledEvent(i int) (*Template, bool) {
+	if rr.templates == nil || rr.templates.events[i] == nil {
+		return nil, false
+	}
+	return rr.templates.events[i], true
+}
@@ -10,37 +10,98 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func processString(r *http.Request, src string, reqBody *string) (*string, error) {
+// Template is a response template parsed once and bound to a request on every execution
+type Template struct {
+	tmpl *template.Template
+}
+
+// NewTemplate parses src, reporting syntax errors and unknown functions
+func NewTemplate(src string) (*Template, error) {
 	t, err := template.New("").
-		Funcs(template.FuncMap{
-			"uuid": func(options ...interface{}) (string, error) {
-				return generators.UUID(r.Context(), options...)
-			},
-			"requestVar": func(name string) string {
-				val := chi.URLParam(r, name)
-				return val
-			},
-			"requestHeader": func(key string) string {
-				return r.Header.Get(key)
-			},
-			"requestQuery": func(key string) string {
-				return r.URL.Query().Get(key)
-			},
-			"time": func(options ...interface{}) (string, error) {
-				return generators.Time(r.Context(), options...)
-			},
-			"requestBody": extractors.RequestBody(reqBody),
-		}).
+		Funcs(templateFuncs(nil, nil)).
 		Parse(src)
 	if err != nil {
 		return nil, err
 	}
+	return &Template{t}, nil
+}
+
+// Execute renders the template for the request.
+// The parsed template is cloned so request bound functions can be set without parsing it again.
+func (t *Template) Execute(r *http.Request, reqBody *string) (*string, error) {
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(templateFuncs(r, reqBody))
 
 	builder := &strings.Builder{}
-	err = t.Execute(builder, r)
+	err = tmpl.Execute(builder, r)
 	if err != nil {
 		return nil, err
 	}
 	result := builder.String()
 	return &result, nil
 }
+
+func processString(r *http.Request, src string, reqBody *string) (*string, error) {
+	t, err := NewTemplate(src)
+	if err != nil {
+		return nil, err
+	}
+	return t.Execute(r, reqBody)
+}
+
+func templateFuncs(r *http.Request, reqBody *string) template.FuncMap {
+	return template.FuncMap{
+		"uuid": func(options ...interface{}) (string, error) {
+			return generators.UUID(r.Context(), options...)
+		},
+		"requestVar": func(name string) string {
+			val := chi.URLParam(r, name)
+			return val
+		},
+		"requestHeader": func(key string) string {
+			return r.Header.Get(key)
+		},
+		"requestQuery": func(key string) string {
+			return r.URL.Query().Get(key)
+		},
+		"time": func(options ...interface{}) (string, error) {
+			return generators.Time(r.Context(), options...)
+		},
+		"parseTime": generators.ParseTime,
+		"fake": func(options ...interface{}) (string, error) {
+			return generators.Fake(r.Context(), options...)
+		},
+		"randomInt": func(options ...interface{}) (string, error) {
+			return generators.RandomInt(r.Context(), options...)
+		},
+		"randomFloat": func(options ...interface{}) (string, error) {
+			return generators.RandomFloat(r.Context(), options...)
+		},
+		"randomChoice": func(options ...interface{}) (string, error) {
+			return generators.RandomChoice(r.Context(), options...)
+		},
+		"randomBool": func(options ...interface{}) (string, error) {
+			return generators.RandomBool(r.Context(), options...)
+		},
+		"randomString": func(options ...interface{}) (string, error) {
+			return generators.RandomString(r.Context(), options...)
+		},
+		"add":         add,
+		"sub":         sub,
+		"mul":         mul,
+		"div":         div,
+		"mod":         mod,
+		"round":       round,
+		"requestBody": extractors.RequestBody(reqBody),
+		"graphqlOperation": func() string {
+			return ParseGraphQLRequest(r, *reqBody).Operation()
+		},
+		"graphqlVariable": func(params ...interface{}) (interface{}, error) {
+			vars := string(ParseGraphQLRequest(r, *reqBody).Variables)
+			return extractors.RequestBody(&vars)(params...)
+		},
+	}
+}
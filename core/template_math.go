@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bmviniciuss/forger/core/generators"
+)
+
+// arithmetic folds the operands left to right with op, using intOp while
+// every operand is an integer, so totals and page counts print without decimals
+func arithmetic(name string, intOp func(a, b int64) (int64, error), op func(a, b float64) (float64, error)) func(a interface{}, rest ...interface{}) (interface{}, error) {
+	return func(a interface{}, rest ...interface{}) (interface{}, error) {
+		acc, err := generators.ToNumber(a)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, v := range rest {
+			b, err := generators.ToNumber(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if acc.IsInt && b.IsInt && intOp != nil {
+				i, err := intOp(acc.Int, b.Int)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				acc = generators.IntNumber(i)
+				continue
+			}
+			f, err := op(acc.Float, b.Float)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			acc = generators.FloatNumber(f)
+		}
+		return acc.Value(), nil
+	}
+}
+
+var errDivisionByZero = errors.New("division by zero")
+
+var (
+	add = arithmetic("add",
+		func(a, b int64) (int64, error) { return a + b, nil },
+		func(a, b float64) (float64, error) { return a + b, nil })
+	sub = arithmetic("sub",
+		func(a, b int64) (int64, error) { return a - b, nil },
+		func(a, b float64) (float64, error) { return a - b, nil })
+	mul = arithmetic("mul",
+		func(a, b int64) (int64, error) { return a * b, nil },
+		func(a, b float64) (float64, error) { return a * b, nil })
+	// div always divides as floats, so 7 / 2 is 3.5; round it for an integer
+	div = arithmetic("div", nil, func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, errDivisionByZero
+		}
+		return a / b, nil
+	})
+	mod = arithmetic("mod",
+		func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, errDivisionByZero
+			}
+			return a % b, nil
+		},
+		func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errDivisionByZero
+			}
+			return math.Mod(a, b), nil
+		})
+)
+
+// round rounds half away from zero to the number of decimals optionally
+// given, returning an integer when there are none
+func round(v interface{}, decimals ...interface{}) (interface{}, error) {
+	n, err := generators.ToNumber(v)
+	if err != nil {
+		return nil, fmt.Errorf("round: %w", err)
+	}
+	if len(decimals) == 0 {
+		return int64(math.Round(n.Float)), nil
+	}
+	d, err := generators.ToNumber(decimals[0])
+	if err != nil || !d.IsInt {
+		return nil, fmt.Errorf("round: invalid decimals %v", decimals[0])
+	}
+	if d.Int <= 0 {
+		return int64(math.Round(n.Float)), nil
+	}
+	pow := math.Pow(10, float64(d.Int))
+	return math.Round(n.Float*pow) / pow, nil
+}
@@ -0,0 +1,55 @@
+package core
+
+import "time"
+
+// Throttle controls how the body of a response is written:
+//
+//	BytesPerSecond  limits the rate the body is written at
+//	ChunkSize       writes the body in chunks of that many bytes with chunked
+//	                transfer encoding, flushing each one
+//	ChunkDelay      pauses between chunks
+//	FirstByteDelay  pauses between writing the headers and the first body byte,
+//	                on top of the response delay
+//
+// When only BytesPerSecond is set, the body is written in chunks of a tenth of it.
+type Throttle struct {
+	BytesPerSecond int
+	ChunkSize      int
+	ChunkDelay     time.Duration
+	FirstByteDelay time.Duration
+}
+
+// DefaultThrottleTicks is how many chunks per second are written when
+// a throttle only sets BytesPerSecond
+const DefaultThrottleTicks = 10
+
+func (t Throttle) Validate() error {
+	if t.BytesPerSecond < 0 || t.ChunkSize < 0 || t.ChunkDelay < 0 || t.FirstByteDelay < 0 {
+		return ErrInvalidThrottle
+	}
+	return nil
+}
+
+// Chunked reports whether the body is written in chunks instead of at once
+func (t Throttle) Chunked() bool {
+	return t.ChunkSize > 0 || t.BytesPerSecond > 0
+}
+
+// Chunk returns how many bytes are written at a time
+func (t Throttle) Chunk() int {
+	if t.ChunkSize > 0 {
+		return t.ChunkSize
+	}
+	return max(t.BytesPerSecond/DefaultThrottleTicks, 1)
+}
+
+// Wait returns how long to pause after writing the chunk that brought the
+// body to written bytes, elapsed being the time spent writing it so far
+func (t Throttle) Wait(written int, elapsed time.Duration) time.Duration {
+	wait := t.ChunkDelay
+	if t.BytesPerSecond > 0 {
+		due := time.Duration(float64(written) / float64(t.BytesPerSecond) * float64(time.Second))
+		wait = max(wait, due-elapsed)
+	}
+	return wait
+}
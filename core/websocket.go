@@ -0,0 +1,218 @@
+package core
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// WebSocketScript is the conversation WEBSOCKET responses run once the
+// connection is upgraded:
+//
+//	OnConnect  messages sent right after the upgrade, in order
+//	Replies    messages sent back for incoming messages, the first matching reply wins
+//	Pushes     messages sent by the server on its own, on a timer
+//
+// Message data may be a template. The upgrade request is the template request,
+// and for replies the incoming message is its body, so requestBody reads from it.
+type WebSocketScript struct {
+	OnConnect []WebSocketMessage
+	Replies   []WebSocketReply
+	Pushes    []WebSocketPush
+}
+
+// WebSocketMessage is a text message sent after waiting Delay
+type WebSocketMessage struct {
+	Data  string
+	Delay time.Duration
+
+	data *Template
+}
+
+// WebSocketReply sends Messages when an incoming message matches every matcher
+type WebSocketReply struct {
+	Matchers []MessageMatcher
+	Messages []WebSocketMessage
+}
+
+// WebSocketPush sends Data After the connection is upgraded and, when Interval
+// is set, again every Interval until the connection is closed
+type WebSocketPush struct {
+	Data     string
+	After    time.Duration
+	Interval time.Duration
+
+	data *Template
+}
+
+// MessageMatcher is a condition an incoming WebSocket message must satisfy.
+// Key is a gjson path into JSON messages; when empty the whole text is matched.
+type MessageMatcher struct {
+	Key      string
+	Operator MatchOperator
+	Value    string
+
+	regex *regexp.Regexp
+}
+
+func (m MessageMatcher) Matches(message string) (bool, error) {
+	value := message
+	if m.Key != "" {
+		res := gjson.Get(message, m.Key)
+		if !res.Exists() {
+			return false, nil
+		}
+		value = res.String()
+	}
+	return matchValue(m.Operator, m.Value, value, m.regex)
+}
+
+// Validate checks the operator and compiles the pattern of matchers used on
+// their own, like the ones of gRPC stubs, which are not part of a WebSocketScript
+func (m *MessageMatcher) Validate() error {
+	if _, err := NewMatchOperator(m.Operator.String()); err != nil {
+		return err
+	}
+	return m.Compile()
+}
+
+// Compile parses the pattern of REGEX matchers once, so it is not parsed
+// again on each message
+func (m *MessageMatcher) Compile() error {
+	if m.Operator != MATCH_OPERATOR_REGEX {
+		return nil
+	}
+	regex, err := regexp.Compile(m.Value)
+	if err != nil {
+		return err
+	}
+	m.regex = regex
+	return nil
+}
+
+// Reply returns the reply for an incoming message, or nil when none matches
+func (s WebSocketScript) Reply(message string) (*WebSocketReply, error) {
+	for i, reply := range s.Replies {
+		matched := true
+		for _, m := range reply.Matchers {
+			ok, err := m.Matches(message)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return &s.Replies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Render executes the message data, message being the incoming message
+// it replies to, if any
+func (m WebSocketMessage) Render(r *http.Request, message string) (string, error) {
+	return renderData(r, m.data, m.Data, message)
+}
+
+func (p WebSocketPush) Render(r *http.Request) (string, error) {
+	return renderData(r, p.data, p.Data, "")
+}
+
+// renderData executes tmpl, the compiled data, or parses data when the
+// script was not compiled
+func renderData(r *http.Request, tmpl *Template, data, body string) (string, error) {
+	if !isTemplate(data) {
+		return data, nil
+	}
+	var rendered *string
+	var err error
+	if tmpl != nil {
+		rendered, err = tmpl.Execute(r, &body)
+	} else {
+		rendered, err = processString(r, data, &body)
+	}
+	if err != nil {
+		return "", err
+	}
+	return *rendered, nil
+}
+
+// Compile returns a copy of the script with its templated data parsed and its
+// REGEX matchers compiled, so they are not parsed again on each message
+func (s WebSocketScript) Compile() (*WebSocketScript, error) {
+	var err error
+	compiled := WebSocketScript{}
+	if compiled.OnConnect, err = compileMessages(s.OnConnect); err != nil {
+		return nil, err
+	}
+	for _, reply := range s.Replies {
+		matchers := make([]MessageMatcher, len(reply.Matchers))
+		copy(matchers, reply.Matchers)
+		for i := range matchers {
+			if err := matchers[i].Compile(); err != nil {
+				return nil, err
+			}
+		}
+		messages, err := compileMessages(reply.Messages)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Replies = append(compiled.Replies, WebSocketReply{Matchers: matchers, Messages: messages})
+	}
+	for _, p := range s.Pushes {
+		if p.data, err = compileData(p.Data); err != nil {
+			return nil, err
+		}
+		compiled.Pushes = append(compiled.Pushes, p)
+	}
+	return &compiled, nil
+}
+
+func compileMessages(messages []WebSocketMessage) ([]WebSocketMessage, error) {
+	var compiled []WebSocketMessage
+	for _, m := range messages {
+		data, err := compileData(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		m.data = data
+		compiled = append(compiled, m)
+	}
+	return compiled, nil
+}
+
+func compileData(data string) (*Template, error) {
+	if !isTemplate(data) {
+		return nil, nil
+	}
+	return NewTemplate(data)
+}
+
+func (s WebSocketScript) Validate() error {
+	messages := append([]WebSocketMessage{}, s.OnConnect...)
+	for _, reply := range s.Replies {
+		for _, m := range reply.Matchers {
+			if _, err := NewMatchOperator(m.Operator.String()); err != nil {
+				return err
+			}
+		}
+		messages = append(messages, reply.Messages...)
+	}
+	for _, m := range messages {
+		if m.Delay < 0 {
+			return ErrInvalidWebSocketScript
+		}
+	}
+	for _, p := range s.Pushes {
+		if p.After < 0 || p.Interval < 0 {
+			return ErrInvalidWebSocketScript
+		}
+	}
+	_, err := s.Compile()
+	return err
+}
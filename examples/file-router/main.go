@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/mux"
+)
+
+const routesDir = "./examples/file-router/routes"
+
+func main() {
+	loader, err := loaders.NewWatchingLoader(loaders.DefaultWatchInterval, routesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer loader.Close()
+
+	r := mux.NewDynamicRouter(loader)
+	fmt.Println("Server started at http://localhost:3000")
+	http.ListenAndServe(":3000", r)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/bmviniciuss/forger/grpcmock"
+)
+
+const (
+	protosPath = "./examples/grpc-mock/protos"
+	stubsPath  = "./examples/grpc-mock/stubs.yaml"
+)
+
+func main() {
+	err := run()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	files, err := grpcmock.LoadProtoFiles(context.Background(), []string{protosPath}, "items.proto")
+	if err != nil {
+		return err
+	}
+	stubs, err := grpcmock.ReadStubs(stubsPath)
+	if err != nil {
+		return err
+	}
+	server, err := grpcmock.NewServer(files, stubs)
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", ":50051")
+	if err != nil {
+		return err
+	}
+	fmt.Println("Serving gRPC stubs at localhost:50051")
+	return server.Serve(lis)
+}
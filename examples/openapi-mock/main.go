@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/bmviniciuss/forger/openapi"
+)
+
+func main() {
+	err := run()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	spec := os.Getenv("OPENAPI_SPEC")
+	if spec == "" {
+		return errors.New("OPENAPI_SPEC is required")
+	}
+	doc, err := openapi.Load(context.Background(), spec)
+	if err != nil {
+		return err
+	}
+	defs, err := openapi.Import(doc)
+	if err != nil {
+		return err
+	}
+	validator, err := openapi.NewValidator(doc)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Mocking %d route(s) of %s at http://localhost:3000\n", len(defs), spec)
+	return http.ListenAndServe(":3000", mux.NewStaticRouter(defs, mux.WithRequestValidation(validator)))
+}
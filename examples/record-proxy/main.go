@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/bmviniciuss/forger/recording"
+)
+
+const outputPath = "./examples/record-proxy/recorded.yaml"
+
+func main() {
+	err := run()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	upstream := os.Getenv("UPSTREAM_URL")
+	if upstream == "" {
+		return errors.New("UPSTREAM_URL is required")
+	}
+
+	rec := recording.NewRecorder()
+	r := mux.NewStaticRouter(recording.ProxyDefinitions(upstream), mux.WithRecorder(rec))
+	server := &http.Server{Addr: ":3000", Handler: r}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Recording %s at http://localhost:3000, press Ctrl+C to save\n", upstream)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	log.Printf("Saving %d recorded route(s) to %s", len(rec.Definitions()), outputPath)
+	return rec.Save(outputPath)
+}
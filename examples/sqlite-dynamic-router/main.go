@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
 	"github.com/bmviniciuss/forger/mux"
 	"github.com/bmviniciuss/forger/pkg/path"
 	_ "github.com/mattn/go-sqlite3"
@@ -26,6 +27,9 @@ const (
 	dbDriver       = "sqlite3"
 	dbPath         = "./examples/forger.db"
 	initScriptPath = "./examples/sqlite-dynamic-router/scripts/01-ddl.sql"
+
+	cacheTTL        = 5 * time.Second
+	cacheMaxEntries = 100
 )
 
 func run() error {
@@ -35,7 +39,7 @@ func run() error {
 	}
 	defer db.Close()
 
-	loader := NewSQLiteLoader(db)
+	loader := loaders.NewCachingLoader(NewSQLiteLoader(db), cacheTTL, cacheMaxEntries)
 	mux := mux.NewDynamicRouter(loader)
 
 	fmt.Println("Server started at http://localhost:3000")
@@ -0,0 +1,60 @@
+package grpcmock
+
+import (
+	"context"
+	"os"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadProtoFiles compiles .proto files, looking them and their imports up
+// in importPaths. The well known google/protobuf imports are always available.
+func LoadProtoFiles(ctx context.Context, importPaths []string, files ...string) (*protoregistry.Files, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+	}
+	compiled, err := compiler.Compile(ctx, files...)
+	if err != nil {
+		return nil, err
+	}
+	registry := new(protoregistry.Files)
+	for _, fd := range compiled {
+		if err := registerFile(registry, fd); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// registerFile registers a file after its imports, skipping registered files
+func registerFile(registry *protoregistry.Files, fd protoreflect.FileDescriptor) error {
+	if _, err := registry.FindFileByPath(fd.Path()); err == nil {
+		return nil
+	}
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if err := registerFile(registry, imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+	return registry.RegisterFile(fd)
+}
+
+// LoadDescriptorSet reads a binary FileDescriptorSet, as written by
+// protoc --descriptor_set_out with --include_imports
+func LoadDescriptorSet(path string) (*protoregistry.Files, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFiles(&set)
+}
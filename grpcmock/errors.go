@@ -0,0 +1,9 @@
+package grpcmock
+
+import "errors"
+
+var (
+	ErrUnknownMethod     = errors.New("method not found in the loaded descriptors")
+	ErrUnsupportedMethod = errors.New("only unary and server streaming methods can be mocked")
+	ErrInvalidStub       = errors.New("stubs require a response, a stream or an error code")
+)
@@ -0,0 +1,99 @@
+package grpcmock
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/bmviniciuss/forger/core/loaders"
+	"gopkg.in/yaml.v3"
+)
+
+// FileDocument is a YAML or JSON document with a top level "stubs" list
+type FileDocument struct {
+	Stubs []FileStub `json:"stubs" yaml:"stubs"`
+}
+
+// FileStub mirrors Stub. Response and stream data may be strings or any
+// structured value, which is encoded as JSON. Code is a gRPC code name such
+// as NOT_FOUND and durations are strings such as "150ms".
+type FileStub struct {
+	Service  string                       `json:"service" yaml:"service"`
+	Method   string                       `json:"method" yaml:"method"`
+	Matchers []loaders.FileMessageMatcher `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Response interface{}                  `json:"response,omitempty" yaml:"response,omitempty"`
+	Stream   []FileStreamMessage          `json:"stream,omitempty" yaml:"stream,omitempty"`
+	Code     string                       `json:"code,omitempty" yaml:"code,omitempty"`
+	Message  string                       `json:"message,omitempty" yaml:"message,omitempty"`
+	Delay    string                       `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+type FileStreamMessage struct {
+	Data  interface{} `json:"data" yaml:"data"`
+	Delay string      `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+// ReadStubs reads the stubs of a .yaml, .yml or .json file
+func ReadStubs(path string) ([]Stub, error) {
+	format, ok := loaders.FormatFromExt(path)
+	if !ok {
+		return nil, loaders.ErrUnsupportedFileFormat
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc FileDocument
+	switch format {
+	case loaders.FORMAT_YAML:
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stubs := make([]Stub, len(doc.Stubs))
+	for i, f := range doc.Stubs {
+		stub, err := f.Stub()
+		if err != nil {
+			return nil, err
+		}
+		stubs[i] = *stub
+	}
+	return stubs, nil
+}
+
+func (f FileStub) Stub() (*Stub, error) {
+	s := &Stub{Service: f.Service, Method: f.Method, Message: f.Message}
+	var err error
+	for _, fm := range f.Matchers {
+		matcher, err := fm.Matcher()
+		if err != nil {
+			return nil, err
+		}
+		s.Matchers = append(s.Matchers, *matcher)
+	}
+	if s.Response, err = loaders.EncodeBody(f.Response); err != nil {
+		return nil, err
+	}
+	for _, fm := range f.Stream {
+		m := StreamMessage{}
+		if m.Data, err = loaders.EncodeBody(fm.Data); err != nil {
+			return nil, err
+		}
+		if m.Delay, err = loaders.ParseDuration(fm.Delay); err != nil {
+			return nil, err
+		}
+		s.Stream = append(s.Stream, m)
+	}
+	if f.Code != "" {
+		if err := s.Code.UnmarshalJSON([]byte(strconv.Quote(f.Code))); err != nil {
+			return nil, err
+		}
+	}
+	if s.Delay, err = loaders.ParseDuration(f.Delay); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,131 @@
+package grpcmock
+
+import (
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/bmviniciuss/forger/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var requestJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// Server is a gRPC server answering the methods of the loaded descriptors
+// with stubs. Like route definitions, the first stub of a method whose
+// matchers are satisfied by the request wins.
+type Server struct {
+	stubs   []Stub
+	methods map[string]protoreflect.MethodDescriptor
+	grpc    *grpc.Server
+}
+
+// NewServer validates the stubs against the descriptors and creates the server
+func NewServer(files *protoregistry.Files, stubs []Stub, opts ...grpc.ServerOption) (*Server, error) {
+	compiled, err := compileStubs(files, stubs)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{stubs: compiled, methods: make(map[string]protoreflect.MethodDescriptor)}
+	for _, stub := range compiled {
+		desc, _ := files.FindDescriptorByName(protoreflect.FullName(stub.Service))
+		method := desc.(protoreflect.ServiceDescriptor).Methods().ByName(protoreflect.Name(stub.Method))
+		s.methods[stub.FullMethod()] = method
+	}
+	s.grpc = grpc.NewServer(append(opts, grpc.UnknownServiceHandler(s.handle))...)
+	return s, nil
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+func (s *Server) GracefulStop() {
+	s.grpc.GracefulStop()
+}
+
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+func (s *Server) handle(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	log.Printf("Handling gRPC method %s", fullMethod)
+	method, ok := s.methods[fullMethod]
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "no stub for method %s", fullMethod)
+	}
+	in := dynamicpb.NewMessage(method.Input())
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	raw, err := requestJSON.Marshal(in)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	request := string(raw)
+	stub, err := s.match(fullMethod, request)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if stub == nil {
+		return status.Errorf(codes.Unimplemented, "no stub of method %s matches the request", fullMethod)
+	}
+
+	ctx := stream.Context()
+	if err := core.Sleep(ctx, stub.Delay); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if stub.Response != "" {
+		if err := s.send(stream, method, *stub, 0, request); err != nil {
+			return err
+		}
+	}
+	for i, m := range stub.Stream {
+		if err := core.Sleep(ctx, m.Delay); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := s.send(stream, method, *stub, i+1, request); err != nil {
+			return err
+		}
+	}
+	if stub.Code != codes.OK {
+		return status.Error(stub.Code, stub.Message)
+	}
+	return nil
+}
+
+func (s *Server) match(fullMethod, request string) (*Stub, error) {
+	for i := range s.stubs {
+		if s.stubs[i].FullMethod() != fullMethod {
+			continue
+		}
+		ok, err := s.stubs[i].matches(request)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return &s.stubs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// send renders a message of the stub and converts it into the method output
+func (s *Server) send(stream grpc.ServerStream, method protoreflect.MethodDescriptor, stub Stub, i int, request string) error {
+	rendered, err := stub.render(stream.Context(), i, request)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	out := dynamicpb.NewMessage(method.Output())
+	if err := protojson.Unmarshal([]byte(rendered), out); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("response is not a valid %s: %s", method.Output().FullName(), err))
+	}
+	return stream.SendMsg(out)
+}
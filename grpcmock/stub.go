@@ -0,0 +1,141 @@
+package grpcmock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Stub is the mocked behaviour of a gRPC method.
+//
+// Response is the JSON of the response message of unary methods and Stream
+// the messages sent by server streaming methods. Both may be templates with
+// the same functions as HTTP responses: the request message, encoded as JSON
+// with the field names of the .proto file, is the request body and the
+// incoming metadata are the request headers.
+//
+// A Code other than OK ends the call with that status and Message after
+// sending the stream, if any.
+type Stub struct {
+	// Service is the fully qualified service name, e.g. shop.v1.Items
+	Service  string
+	Method   string
+	Matchers []core.MessageMatcher
+	Response string
+	Stream   []StreamMessage
+	Code     codes.Code
+	Message  string
+	Delay    time.Duration
+
+	templates []*core.Template
+}
+
+// StreamMessage is a message sent by server streaming methods after waiting Delay
+type StreamMessage struct {
+	Data  string
+	Delay time.Duration
+}
+
+// FullMethod returns the method name as seen by gRPC handlers, e.g. /shop.v1.Items/GetItem
+func (s Stub) FullMethod() string {
+	return "/" + s.Service + "/" + s.Method
+}
+
+// compile checks the stub against the method it mocks and parses its templates
+func (s *Stub) compile(files *protoregistry.Files) error {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(s.Service))
+	if err != nil {
+		return ErrUnknownMethod
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return ErrUnknownMethod
+	}
+	method := service.Methods().ByName(protoreflect.Name(s.Method))
+	if method == nil {
+		return ErrUnknownMethod
+	}
+	if method.IsStreamingClient() || (len(s.Stream) > 0 && !method.IsStreamingServer()) {
+		return ErrUnsupportedMethod
+	}
+	if s.Response == "" && len(s.Stream) == 0 && s.Code == codes.OK {
+		return ErrInvalidStub
+	}
+	if s.Delay < 0 {
+		return ErrInvalidStub
+	}
+	matchers := make([]core.MessageMatcher, len(s.Matchers))
+	copy(matchers, s.Matchers)
+	for i := range matchers {
+		if err := matchers[i].Validate(); err != nil {
+			return err
+		}
+	}
+	s.Matchers = matchers
+	sources := []string{s.Response}
+	for _, m := range s.Stream {
+		if m.Delay < 0 {
+			return ErrInvalidStub
+		}
+		sources = append(sources, m.Data)
+	}
+	s.templates = make([]*core.Template, len(sources))
+	for i, src := range sources {
+		if s.templates[i], err = core.NewTemplate(src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches reports whether the request message, encoded as JSON, satisfies every matcher
+func (s Stub) matches(request string) (bool, error) {
+	for _, m := range s.Matchers {
+		ok, err := m.Matches(request)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// render executes the template of the response, when i is 0,
+// or of the stream message i-1
+func (s Stub) render(ctx context.Context, i int, request string) (string, error) {
+	rendered, err := s.templates[i].Execute(templateRequest(ctx, s.FullMethod(), request), &request)
+	if err != nil {
+		return "", err
+	}
+	return *rendered, nil
+}
+
+// templateRequest builds the request templates are executed for
+func templateRequest(ctx context.Context, fullMethod, body string) *http.Request {
+	r, _ := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, strings.NewReader(body))
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, v := range values {
+			r.Header.Add(key, v)
+		}
+	}
+	return r
+}
+
+func compileStubs(files *protoregistry.Files, stubs []Stub) ([]Stub, error) {
+	compiled := make([]Stub, len(stubs))
+	copy(compiled, stubs)
+	for i := range compiled {
+		if err := compiled[i].compile(files); err != nil {
+			return nil, fmt.Errorf("stub %d (%s): %w", i, compiled[i].FullMethod(), err)
+		}
+	}
+	return compiled, nil
+}
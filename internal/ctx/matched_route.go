@@ -0,0 +1,24 @@
+package ctx
+
+import (
+	"context"
+)
+
+var (
+	matchedRouteKey = key("matched_route")
+)
+
+// MatchedRoute is filled by the router with the route definition that handled the request
+type MatchedRoute struct {
+	Method string
+	Path   string
+}
+
+func WithMatchedRoute(c context.Context, route *MatchedRoute) context.Context {
+	return context.WithValue(c, matchedRouteKey, route)
+}
+
+func GetMatchedRoute(c context.Context) (*MatchedRoute, bool) {
+	val, ok := c.Value(matchedRouteKey).(*MatchedRoute)
+	return val, ok
+}
@@ -0,0 +1,7 @@
+package journal
+
+import "errors"
+
+var (
+	ErrVerificationFailed = errors.New("request verification failed")
+)
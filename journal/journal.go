@@ -0,0 +1,168 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+)
+
+const DefaultCapacity = 1000
+
+// Entry is a request received by the router
+type Entry struct {
+	RequestID  string        `json:"request_id"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	Query      string        `json:"query,omitempty"`
+	Headers    http.Header   `json:"headers"`
+	Body       string        `json:"body,omitempty"`
+	Route      string        `json:"route,omitempty"`
+	StatusCode int           `json:"status_code"`
+	ReceivedAt time.Time     `json:"received_at"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Request rebuilds the received request so it can be checked with core.RequestMatcher
+func (e Entry) Request() *http.Request {
+	r := &http.Request{
+		Method: e.Method,
+		URL:    &url.URL{Path: e.Path, RawQuery: e.Query},
+		Header: e.Headers.Clone(),
+		Body:   http.NoBody,
+	}
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+	if e.Body != "" {
+		r.Body = io.NopCloser(strings.NewReader(e.Body))
+	}
+	return r
+}
+
+// Query selects journal entries. Empty fields match any entry.
+type Query struct {
+	Method string
+	// Path is the exact path of the request, e.g. "/orders/1"
+	Path string
+	// Route is the path of the route definition that handled the request, e.g. "/orders/{id}"
+	Route    string
+	Matchers []core.RequestMatcher
+}
+
+// Matches reports whether the entry satisfies the query
+func (q Query) Matches(e Entry) (bool, error) {
+	if q.Method != "" && !strings.EqualFold(q.Method, e.Method) {
+		return false, nil
+	}
+	if q.Path != "" && q.Path != e.Path {
+		return false, nil
+	}
+	if q.Route != "" && q.Route != e.Route {
+		return false, nil
+	}
+	if len(q.Matchers) == 0 {
+		return true, nil
+	}
+	r := e.Request()
+	for _, m := range q.Matchers {
+		ok, err := m.Matches(r)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Journal keeps the last received requests, dropping the oldest ones once full
+type Journal struct {
+	mu       sync.RWMutex
+	capacity int
+	entries  []Entry
+	next     int
+	full     bool
+}
+
+// New creates a journal holding up to capacity entries.
+// A non positive capacity uses DefaultCapacity.
+func New(capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Journal{
+		capacity: capacity,
+		entries:  make([]Entry, capacity),
+	}
+}
+
+func (j *Journal) Record(e Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries[j.next] = e
+	j.next = (j.next + 1) % j.capacity
+	if j.next == 0 {
+		j.full = true
+	}
+}
+
+// Entries returns the recorded entries from the oldest to the newest
+func (j *Journal) Entries() []Entry {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if !j.full {
+		entries := make([]Entry, j.next)
+		copy(entries, j.entries[:j.next])
+		return entries
+	}
+	entries := make([]Entry, 0, j.capacity)
+	entries = append(entries, j.entries[j.next:]...)
+	return append(entries, j.entries[:j.next]...)
+}
+
+// Find returns the entries matching the query, from the oldest to the newest
+func (j *Journal) Find(q Query) ([]Entry, error) {
+	found := []Entry{}
+	for _, e := range j.Entries() {
+		ok, err := q.Matches(e)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found = append(found, e)
+		}
+	}
+	return found, nil
+}
+
+func (j *Journal) Count(q Query) (int, error) {
+	found, err := j.Find(q)
+	if err != nil {
+		return 0, err
+	}
+	return len(found), nil
+}
+
+// Verify checks that exactly expected entries match the query
+func (j *Journal) Verify(q Query, expected int) error {
+	count, err := j.Count(q)
+	if err != nil {
+		return err
+	}
+	if count != expected {
+		return fmt.Errorf("%w: expected %d request(s), got %d", ErrVerificationFailed, expected, count)
+	}
+	return nil
+}
+
+func (j *Journal) Reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = make([]Entry, j.capacity)
+	j.next = 0
+	j.full = false
+}
@@ -0,0 +1,55 @@
+package journal
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	t.Run("should keep only the newest entries", func(t *testing.T) {
+		j := New(2)
+		j.Record(Entry{Path: "/1"})
+		j.Record(Entry{Path: "/2"})
+		j.Record(Entry{Path: "/3"})
+		entries := j.Entries()
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "/2", entries[0].Path)
+		assert.Equal(t, "/3", entries[1].Path)
+
+		j.Reset()
+		assert.Empty(t, j.Entries())
+	})
+
+	t.Run("should verify requests matching query", func(t *testing.T) {
+		j := New(0)
+		j.Record(Entry{Method: "POST", Path: "/orders", Body: `{"customer": 42}`})
+		j.Record(Entry{Method: "POST", Path: "/orders", Body: `{"customer": 42}`, Headers: http.Header{"X-Tenant": {"acme"}}})
+		j.Record(Entry{Method: "POST", Path: "/orders", Body: `{"customer": 7}`})
+		j.Record(Entry{Method: "GET", Path: "/orders", Query: "customer=42"})
+
+		q := Query{
+			Method: "post",
+			Path:   "/orders",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_BODY, Key: "customer", Operator: core.MATCH_OPERATOR_EQUALS, Value: "42"},
+			},
+		}
+		assert.Nil(t, j.Verify(q, 2))
+		assert.ErrorIs(t, j.Verify(q, 1), ErrVerificationFailed)
+
+		count, err := j.Count(Query{Matchers: []core.RequestMatcher{
+			{Source: core.MATCHER_SOURCE_QUERY, Key: "customer", Operator: core.MATCH_OPERATOR_EQUALS, Value: "42"},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = j.Count(Query{Matchers: []core.RequestMatcher{
+			{Source: core.MATCHER_SOURCE_HEADER, Key: "x-tenant", Operator: core.MATCH_OPERATOR_EXISTS},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
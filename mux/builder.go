@@ -1,12 +1,15 @@
 package mux
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/bmviniciuss/forger/admin"
 	"github.com/bmviniciuss/forger/core"
 	"github.com/bmviniciuss/forger/core/responses"
+	"github.com/bmviniciuss/forger/internal/ctx"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
@@ -15,68 +18,283 @@ const (
 	utcLayout = "2006-01-02T15:04:05.000Z"
 )
 
-func NewStaticRouter(defs []core.RouteDefinition) *chi.Mux {
+// NewStaticRouter serves defs. Like chi does for invalid patterns, it panics
+// when a definition is invalid, like one with a template that can not be
+// parsed, or an option is; core.ValidateDefinitions reports such definitions
+// as an error instead.
+func NewStaticRouter(defs []core.RouteDefinition, opts ...Option) *chi.Mux {
+	cfg := newConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		panic(err)
+	}
 	router := chi.NewRouter()
-	setMiddlewares(router)
-	registerRoutes(router, defs)
-	setNotFoundHandler(router)
+	setMiddlewares(router, cfg)
+	mountAdmin(router, cfg)
+	if err := registerRoutes(router, defs, cfg, newCallCounter()); err != nil {
+		panic(err)
+	}
+	setNotFoundHandler(router, cfg)
 	return router
 }
 
-func NewDynamicRouter(loader core.Loader) *chi.Mux {
+// NewDynamicRouter serves the definitions of loader, rebuilding its routes
+// whenever they change. It panics when an option is invalid.
+func NewDynamicRouter(loader core.Loader, opts ...Option) *chi.Mux {
+	cfg := newConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		panic(err)
+	}
 	router := chi.NewRouter()
-	setMiddlewares(router)
+	setMiddlewares(router, cfg)
+	mountAdmin(router, cfg)
+	counter := newCallCounter()
+	routers := newRouterCache()
 	router.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
-		defs, err := loader.Load(r)
+		defs, version, err := loadDefinitions(loader, r)
 		if err != nil {
 			log.Default().Printf("Error while getting route definitions from provider: %s", err)
 			render.JSON(w, r, responses.NewInternalErrorResponse("Internal Server Error", "Error while getting route definitions from provider"))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-		subRouter := chi.NewRouter()
-		registerRoutes(subRouter, defs)
+		subRouter, err := routers.get(r.URL.Path, version, func() (*chi.Mux, error) {
+			subRouter := chi.NewRouter()
+			if err := registerRoutes(subRouter, defs, cfg, counter); err != nil {
+				return nil, err
+			}
+			setNotFoundHandler(subRouter, cfg)
+			return subRouter, nil
+		})
+		if err != nil {
+			log.Printf("Error while registering route definitions from provider: %s", err)
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, responses.NewInternalErrorResponse("Internal Server Error", err.Error()))
+			return
+		}
 		subRouter.ServeHTTP(w, r)
 	})
-	setNotFoundHandler(router)
+	setNotFoundHandler(router, cfg)
 	return router
 }
 
-func registerRoutes(router *chi.Mux, defs []core.RouteDefinition) {
-	baseHeaders := map[string]string{
-		"Content-Type": "application/json",
+// registerRoutes validates, compiles and registers every definition, failing
+// on the first invalid one so bad matchers and templates are caught up front
+func registerRoutes(router *chi.Mux, defs []core.RouteDefinition, cfg *config, counter *callCounter) error {
+	groups := groupRoutes(defs)
+	for _, routes := range groups {
+		for i := range routes {
+			if err := routes[i].Validate(); err != nil {
+				return fmt.Errorf("route [%s %s]: %w", routes[i].Method, routes[i].Path, err)
+			}
+			if err := routes[i].Compile(); err != nil {
+				return fmt.Errorf("route [%s %s]: %w", routes[i].Method, routes[i].Path, err)
+			}
+		}
 	}
-	for _, route := range defs {
-		def := route
-		log.Printf("Registering route [%+v]\n\n", def)
-		router.Method(def.Method, def.Path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			log.Printf("Handling route %+v\n\n", def)
-			res, err := def.Response.BuildResponse(r)
+	for _, group := range groups {
+		routes := group
+		log.Printf("Registering route [%s %s] with %d definition(s)\n\n", routes[0].Method, routes[0].Path, len(routes))
+		router.Method(routes[0].Method, routes[0].Path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			i, err := matchRoute(routes, r, cfg.scenarios)
 			if err != nil {
+				render.Status(r, http.StatusInternalServerError)
 				render.JSON(w, r, responses.NewInternalErrorResponse("Internal Server Error", err.Error()))
-				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
-			for k, v := range baseHeaders {
-				w.Header().Set(k, v)
+			if i < 0 {
+				notFoundHandler(cfg)(w, r)
+				return
+			}
+			def := routes[i]
+			if route, ok := ctx.GetMatchedRoute(r.Context()); ok {
+				route.Method, route.Path = def.Method, def.Path
 			}
-			for k, v := range res.Headers {
-				w.Header().Set(k, v)
+			call := counter.next(counterKey(def.Method, def.Path, i))
+			if err := handleRoute(w, r, cfg, def, def.SelectResponse(call)); err != nil {
+				return
 			}
-			if def.Response.Delay > 0 {
-				time.Sleep(def.Response.Delay)
+			if def.Scenario != "" && def.NewState != "" {
+				if err := cfg.scenarios.SetState(r.Context(), def.Scenario, def.NewState); err != nil {
+					log.Printf("Error while moving scenario %s to state %s: %s", def.Scenario, def.NewState, err)
+				}
 			}
-			w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
-			w.WriteHeader(res.StatusCode)
-			w.Write([]byte(*res.Body))
 		}))
 	}
+	return nil
 }
 
-func setNotFoundHandler(router *chi.Mux) {
-	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, responses.NewNotFoundResponse())
-	})
+// groupRoutes groups definitions sharing the same method and path, keeping
+// the order in which they were declared
+func groupRoutes(defs []core.RouteDefinition) [][]core.RouteDefinition {
+	groups := [][]core.RouteDefinition{}
+	index := make(map[string]int)
+	for _, def := range defs {
+		key := def.Method + " " + def.Path
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, []core.RouteDefinition{})
+		}
+		groups[i] = append(groups[i], def)
+	}
+	return groups
+}
+
+// matchRoute returns the index of the first definition whose scenario state
+// and matchers are satisfied by the request, or -1 when none of them is
+func matchRoute(defs []core.RouteDefinition, r *http.Request, scenarios core.ScenarioStore) (int, error) {
+	for i := range defs {
+		if defs[i].Scenario != "" && defs[i].RequiredState != "" {
+			state, err := scenarios.GetState(r.Context(), defs[i].Scenario)
+			if err != nil {
+				return -1, err
+			}
+			if state != defs[i].RequiredState {
+				continue
+			}
+		}
+		ok, err := defs[i].Matches(r)
+		if err != nil {
+			return -1, err
+		}
+		if ok {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// handleRoute writes the response of the definition, returning the error
+// already rendered to the client when the response could not be built
+func handleRoute(w http.ResponseWriter, r *http.Request, cfg *config, def core.RouteDefinition, response core.RouteResponse) error {
+	baseHeaders := map[string]string{
+		"Content-Type": "application/json",
+	}
+	log.Printf("Handling route %+v\n\n", def)
+	r, err := seedRequest(r, cfg, def)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, responses.NewInternalErrorResponse("Internal Server Error", err.Error()))
+		return err
+	}
+	if response.Type == core.RESPONSE_TYPE_WEBSOCKET {
+		if err := serveWebSocket(w, r, *response.WebSocket); err != nil {
+			log.Printf("WebSocket conversation ended with error: %s", err)
+			return err
+		}
+		return nil
+	}
+	res, err := response.BuildResponse(r)
+	if err != nil && response.Type == core.RESPONSE_TYPE_PROXY {
+		log.Printf("Error while forwarding request to upstream: %s", err)
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, responses.NewBadGatewayResponse("Bad Gateway", err.Error()))
+		return err
+	}
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, responses.NewInternalErrorResponse("Internal Server Error", err.Error()))
+		return err
+	}
+	if response.Type == core.RESPONSE_TYPE_PROXY && cfg.recorder != nil {
+		cfg.recorder.Record(r, res)
+	}
+	if response.Type == core.RESPONSE_TYPE_SSE {
+		baseHeaders["Content-Type"] = "text/event-stream"
+		baseHeaders["Cache-Control"] = "no-cache"
+	}
+	for k, v := range baseHeaders {
+		w.Header().Set(k, v)
+	}
+	for k, v := range res.Headers {
+		w.Header().Set(k, v)
+	}
+	if err := core.Sleep(r.Context(), response.SampleDelay(cfg.delay)); err != nil {
+		log.Printf("Request cancelled while delaying response: %s", err)
+		return err
+	}
+	if fault := response.PickFault(); fault != nil {
+		writeFault(w, r, *fault, res)
+		return nil
+	}
+	w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
+	if response.Type == core.RESPONSE_TYPE_SSE {
+		writeEvents(w, r, res, response.Repeat)
+		return nil
+	}
+	writeBody(w, r, res.StatusCode, []byte(*res.Body), response.Throttle)
+	return nil
+}
+
+func mountAdmin(router *chi.Mux, cfg *config) {
+	if cfg.admin != nil {
+		router.Mount(admin.DefaultPath, cfg.admin)
+	}
+}
+
+func setNotFoundHandler(router *chi.Mux, cfg *config) {
+	router.NotFound(notFoundHandler(cfg))
+	if cfg.fallback != "" {
+		router.MethodNotAllowed(notFoundHandler(cfg))
+	}
+}
+
+// notFoundHandler answers requests no route definition matches,
+// forwarding them to the fallback upstream when there is one
+func notFoundHandler(cfg *config) http.HandlerFunc {
+	if cfg.fallback == "" {
+		return notFound
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		forwardToFallback(w, r, cfg)
+	}
+}
+
+func forwardToFallback(w http.ResponseWriter, r *http.Request, cfg *config) {
+	body, err := core.PeekBody(r)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, responses.NewBadRequestResponse("Bad Request", err.Error()))
+		return
+	}
+	res, err := core.Forward(r, cfg.fallback, body)
+	if err != nil {
+		log.Printf("Error while forwarding request to fallback upstream: %s", err)
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, responses.NewBadGatewayResponse("Bad Gateway", err.Error()))
+		return
+	}
+	if cfg.recorder != nil {
+		cfg.recorder.Record(r, res)
+	}
+	for k, v := range res.Headers {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("x-forger-proxied", "true")
+	w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
+	w.WriteHeader(res.StatusCode)
+	w.Write([]byte(*res.Body))
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
+	render.Status(r, http.StatusNotFound)
+	render.JSON(w, r, responses.NewNotFoundResponse())
+}
+
+// seedRequest binds the request to the seed of the definition and the global one
+func seedRequest(r *http.Request, cfg *config, def core.RouteDefinition) (*http.Request, error) {
+	if def.Seed == "" && cfg.seed == nil {
+		return r, nil
+	}
+	body, err := core.PeekBody(r)
+	if err != nil {
+		return r, err
+	}
+	seeded, err := def.SeedRequest(r, body, cfg.seed)
+	if err != nil {
+		return r, err
+	}
+	return seeded, nil
 }
@@ -0,0 +1,30 @@
+package mux
+
+import (
+	"fmt"
+	"sync"
+)
+
+// callCounter keeps how many times each route definition has been served,
+// so routes with several responses can pick the next one
+type callCounter struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func newCallCounter() *callCounter {
+	return &callCounter{counts: make(map[string]uint64)}
+}
+
+// next returns the zero based call number for the key and increments it
+func (c *callCounter) next(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.counts[key]
+	c.counts[key] = n + 1
+	return n
+}
+
+func counterKey(method, path string, index int) string {
+	return fmt.Sprintf("%s %s #%d", method, path, index)
+}
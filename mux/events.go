@@ -0,0 +1,30 @@
+package mux
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core"
+)
+
+// writeEvents streams the events of an SSE response, flushing each one,
+// until they have been sent repeat more times or the client goes away
+func writeEvents(w http.ResponseWriter, r *http.Request, res core.Result, repeat int) {
+	rc := http.NewResponseController(w)
+	w.WriteHeader(res.StatusCode)
+	rc.Flush()
+	for i := 0; repeat == core.REPEAT_FOREVER || i <= repeat; i++ {
+		for _, event := range res.Events {
+			if err := core.Sleep(r.Context(), event.Delay); err != nil {
+				log.Printf("Stopped streaming events: %s", err)
+				return
+			}
+			if _, err := io.WriteString(w, event.Encode()); err != nil {
+				log.Printf("Stopped streaming events: %s", err)
+				return
+			}
+			rc.Flush()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mux
+
+import (
+	"crypto/rand"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+)
+
+// writeFault writes the result of a route misbehaving as described by the fault.
+// Connection level faults hijack the connection; when that is not possible,
+// as with HTTP/2 or recorders, a bodiless 502 closing the connection is written.
+func writeFault(w http.ResponseWriter, r *http.Request, fault core.Fault, res core.Result) {
+	body := []byte(*res.Body)
+	w.Header().Set("x-forger-fault", fault.Type.String())
+	w.Header().Set("x-forger-req-end", time.Now().Format(utcLayout))
+	switch fault.Type {
+	case core.FAULT_TYPE_MALFORMED_BODY:
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(res.StatusCode)
+		w.Write(body[:len(body)/2])
+	case core.FAULT_TYPE_SLOW_DRIP:
+		dripBody(w, r, fault, res.StatusCode, body)
+	default:
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			log.Printf("Connection can not be hijacked for fault %s, answering bad gateway: %s", fault.Type, err)
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer conn.Close()
+		switch fault.Type {
+		case core.FAULT_TYPE_CONNECTION_RESET:
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				tcp.SetLinger(0)
+			}
+		case core.FAULT_TYPE_RANDOM_DATA:
+			garbage := make([]byte, core.DefaultRandomDataSize)
+			rand.Read(garbage)
+			conn.Write(garbage)
+		}
+	}
+}
+
+func dripBody(w http.ResponseWriter, r *http.Request, fault core.Fault, statusCode int, body []byte) {
+	throttle := core.Throttle{ChunkSize: fault.DripBytes, ChunkDelay: fault.DripInterval}
+	if throttle.ChunkSize <= 0 {
+		throttle.ChunkSize = core.DefaultDripBytes
+	}
+	if throttle.ChunkDelay <= 0 {
+		throttle.ChunkDelay = core.DefaultDripInterval
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(statusCode)
+	streamBody(w, r, throttle, body)
+}
@@ -1,20 +1,36 @@
 package mux
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/bmviniciuss/forger/admin"
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/responses"
 	"github.com/bmviniciuss/forger/internal/ctx"
+	"github.com/bmviniciuss/forger/journal"
+	"github.com/bmviniciuss/forger/openapi"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
 	"github.com/google/uuid"
 )
 
-func setMiddlewares(router *chi.Mux) {
+func setMiddlewares(router *chi.Mux, cfg *config) {
 	router.Use(resContentType)
 	router.Use(startTime)
 	router.Use(requestID)
 	router.Use(middleware.Logger)
+	router.Use(matchedRoute)
+	if cfg.journal != nil {
+		router.Use(recordRequest(cfg.journal))
+	}
+	if cfg.validator != nil {
+		router.Use(validateRequest(cfg.validator))
+	}
 }
 
 func requestID(h http.Handler) http.Handler {
@@ -46,3 +62,73 @@ func resContentType(h http.Handler) http.Handler {
 		h.ServeHTTP(w, r)
 	})
 }
+
+func matchedRoute(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(ctx.WithMatchedRoute(r.Context(), &ctx.MatchedRoute{})))
+	})
+}
+
+func recordRequest(j *journal.Journal) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, admin.DefaultPath) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			body, err := core.PeekBody(r)
+			if err != nil {
+				log.Printf("Error while reading request body for the journal: %s", err)
+			}
+			entry := journal.Entry{
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Query:      r.URL.RawQuery,
+				Headers:    r.Header.Clone(),
+				Body:       body,
+				ReceivedAt: time.Now(),
+			}
+			entry.RequestID, _ = ctx.GetRequestID(r.Context())
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			h.ServeHTTP(ww, r)
+
+			entry.Duration = time.Since(entry.ReceivedAt)
+			entry.StatusCode = ww.Status()
+			if entry.StatusCode == 0 {
+				entry.StatusCode = http.StatusOK
+			}
+			if route, ok := ctx.GetMatchedRoute(r.Context()); ok && route.Path != "" {
+				entry.Route = route.Path
+			}
+			j.Record(entry)
+		})
+	}
+}
+
+// validateRequest answers bad request, before any route is matched, to requests
+// that do not conform to the API specification
+func validateRequest(v *openapi.Validator) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, admin.DefaultPath) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			err := v.Validate(r)
+			if err == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			var details []responses.ErrorDetail
+			var validationErr *openapi.ValidationError
+			if errors.As(err, &validationErr) {
+				for _, issue := range validationErr.Issues {
+					details = append(details, responses.ErrorDetail{In: issue.In, Name: issue.Name, Reason: issue.Reason})
+				}
+			}
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, responses.NewInvalidRequestResponse(err.Error(), details))
+		})
+	}
+}
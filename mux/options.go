@@ -0,0 +1,110 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/journal"
+	"github.com/bmviniciuss/forger/openapi"
+	"github.com/bmviniciuss/forger/recording"
+)
+
+type config struct {
+	scenarios core.ScenarioStore
+	admin     http.Handler
+	journal   *journal.Journal
+	recorder  *recording.Recorder
+	fallback  string
+	delay     *core.DelayProfile
+	validator *openapi.Validator
+	seed      *int64
+}
+
+// Option customizes the routers built by NewStaticRouter and NewDynamicRouter
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		scenarios: core.NewInMemoryScenarioStore(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// validate reports options that can not be applied to any request
+func (c *config) validate() error {
+	if c.delay != nil {
+		if err := c.delay.Validate(); err != nil {
+			return fmt.Errorf("delay profile: %w", err)
+		}
+	}
+	return nil
+}
+
+// WithScenarioStore sets the store used to keep scenario states.
+// Defaults to an in-memory store.
+func WithScenarioStore(store core.ScenarioStore) Option {
+	return func(c *config) {
+		c.scenarios = store
+	}
+}
+
+// WithAdmin mounts the admin API, usually built with admin.NewHandler, at admin.DefaultPath.
+// To manage the served definitions at runtime the admin API and NewDynamicRouter
+// must share the same loaders.MemoryLoader.
+func WithAdmin(handler http.Handler) Option {
+	return func(c *config) {
+		c.admin = handler
+	}
+}
+
+// WithJournal records every request served by the router, except the admin API ones, in j
+func WithJournal(j *journal.Journal) Option {
+	return func(c *config) {
+		c.journal = j
+	}
+}
+
+// WithRecorder captures the responses of PROXY routes in rec so they can be replayed later
+func WithRecorder(rec *recording.Recorder) Option {
+	return func(c *config) {
+		c.recorder = rec
+	}
+}
+
+// WithFallbackUpstream forwards every request that no route definition matches
+// to the upstream base URL instead of answering not found.
+// Forwarded responses carry the x-forger-proxied: true header.
+func WithFallbackUpstream(upstream string) Option {
+	return func(c *config) {
+		c.fallback = upstream
+	}
+}
+
+// WithDelayProfile delays every response that does not define its own delay
+// with delays sampled from profile. Routers panic when profile is invalid.
+func WithDelayProfile(profile core.DelayProfile) Option {
+	return func(c *config) {
+		c.delay = &profile
+	}
+}
+
+// WithRequestValidation answers bad request to requests that do not conform
+// to the OpenAPI operations checked by v, listing every issue found
+func WithRequestValidation(v *openapi.Validator) Option {
+	return func(c *config) {
+		c.validator = v
+	}
+}
+
+// WithSeed makes the random values of every response reproducible, so the
+// same request always renders the same uuids and fake data. Definitions with
+// their own seed combine it with this one.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seed = &seed
+	}
+}
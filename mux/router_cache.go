@@ -0,0 +1,68 @@
+package mux
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/pkg/path"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxCachedRouters = 1024
+
+// routerCache keeps the last router compiled for each path prefix, along
+// with the version of the definitions it was compiled for. A router is reused
+// while a core.VersionedLoader keeps reporting the same version.
+type routerCache struct {
+	mu      sync.Mutex
+	routers map[string]cachedRouter
+}
+
+type cachedRouter struct {
+	version uint64
+	router  *chi.Mux
+}
+
+func newRouterCache() *routerCache {
+	return &routerCache{routers: make(map[string]cachedRouter)}
+}
+
+// loadDefinitions loads the definitions for the request, along with their
+// version when the loader reports one, 0 otherwise
+func loadDefinitions(loader core.Loader, r *http.Request) ([]core.RouteDefinition, uint64, error) {
+	if vl, ok := loader.(core.VersionedLoader); ok {
+		return vl.LoadVersion(r)
+	}
+	defs, err := loader.Load(r)
+	return defs, 0, err
+}
+
+// get returns the router compiled for the version of the definitions served
+// for url, calling build when there is none. Unversioned definitions are
+// always built again, and routers failing to build are not cached.
+func (c *routerCache) get(url string, version uint64, build func() (*chi.Mux, error)) (*chi.Mux, error) {
+	if version == 0 {
+		return build()
+	}
+	prefix := path.ExtractPrefix(url)
+
+	c.mu.Lock()
+	cached, ok := c.routers[prefix]
+	c.mu.Unlock()
+	if ok && cached.version == version {
+		return cached.router, nil
+	}
+
+	router, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.routers[prefix]; !ok && len(c.routers) >= maxCachedRouters {
+		c.routers = make(map[string]cachedRouter)
+	}
+	c.routers[prefix] = cachedRouter{version, router}
+	return router, nil
+}
@@ -0,0 +1,53 @@
+package mux
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterCache(t *testing.T) {
+	builds := 0
+	build := func() (*chi.Mux, error) {
+		builds++
+		return chi.NewRouter(), nil
+	}
+	get := func(c *routerCache, url string, version uint64) *chi.Mux {
+		router, err := c.get(url, version, build)
+		assert.Nil(t, err)
+		return router
+	}
+
+	t.Run("should reuse routers while the version is the same", func(t *testing.T) {
+		builds = 0
+		c := newRouterCache()
+		first := get(c, "/items/1", 1)
+		assert.Same(t, first, get(c, "/items/2", 1))
+		assert.Equal(t, 1, builds)
+
+		assert.NotSame(t, first, get(c, "/items/1", 2))
+		get(c, "/orders", 2)
+		assert.Equal(t, 3, builds)
+		assert.Len(t, c.routers, 2)
+	})
+
+	t.Run("should always build routers of unversioned definitions", func(t *testing.T) {
+		builds = 0
+		c := newRouterCache()
+		get(c, "/items", 0)
+		get(c, "/items", 0)
+		assert.Equal(t, 2, builds)
+		assert.Empty(t, c.routers)
+	})
+
+	t.Run("should not cache routers failing to build", func(t *testing.T) {
+		c := newRouterCache()
+		_, err := c.get("/items", 1, func() (*chi.Mux, error) {
+			return nil, errors.New("bad template")
+		})
+		assert.EqualError(t, err, "bad template")
+		assert.Empty(t, c.routers)
+	})
+}
@@ -0,0 +1,55 @@
+package mux
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+)
+
+// writeBody writes the status code and the body of a response,
+// streaming it when the response is throttled
+func writeBody(w http.ResponseWriter, r *http.Request, statusCode int, body []byte, throttle *core.Throttle) {
+	w.WriteHeader(statusCode)
+	if throttle == nil {
+		w.Write(body)
+		return
+	}
+	if err := streamBody(w, r, *throttle, body); err != nil {
+		log.Printf("Stopped streaming response body: %s", err)
+	}
+}
+
+// streamBody writes body as described by the throttle, flushing each chunk.
+// Unless a Content-Length header was set, chunks are sent with chunked transfer encoding.
+// It stops early when the request is cancelled or the client goes away.
+func streamBody(w http.ResponseWriter, r *http.Request, t core.Throttle, body []byte) error {
+	rc := http.NewResponseController(w)
+	if t.FirstByteDelay > 0 {
+		rc.Flush()
+		if err := core.Sleep(r.Context(), t.FirstByteDelay); err != nil {
+			return err
+		}
+	}
+	if !t.Chunked() {
+		_, err := w.Write(body)
+		return err
+	}
+	size := t.Chunk()
+	start := time.Now()
+	for offset := 0; offset < len(body); offset += size {
+		end := min(offset+size, len(body))
+		if _, err := w.Write(body[offset:end]); err != nil {
+			return err
+		}
+		rc.Flush()
+		if end == len(body) {
+			break
+		}
+		if err := core.Sleep(r.Context(), t.Wait(end, time.Since(start))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
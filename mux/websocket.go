@@ -0,0 +1,119 @@
+package mux
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/gorilla/websocket"
+)
+
+// upgrader accepts connections from any origin, as mocked backends are
+// usually called by front ends served from somewhere else
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsWriteTimeout bounds how long a message may block on a slow client
+const wsWriteTimeout = 10 * time.Second
+
+// wsConn serializes writes, which may come from the conversation and the pushes
+type wsConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsConn) send(data string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(data))
+}
+
+// serveWebSocket upgrades the connection and runs the script until the
+// client closes it. Replies are sent in the order messages arrive, so a
+// delayed reply holds back the following ones.
+func serveWebSocket(w http.ResponseWriter, r *http.Request, script core.WebSocketScript) error {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered with an error
+		return err
+	}
+	defer conn.Close()
+	ws := &wsConn{conn: conn}
+	ctx, cancel := context.WithCancel(r.Context())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	for _, push := range script.Pushes {
+		wg.Add(1)
+		go func(push core.WebSocketPush) {
+			defer wg.Done()
+			runPush(ctx, r, ws, push)
+		}(push)
+	}
+	if err := sendMessages(ctx, r, ws, script.OnConnect, ""); err != nil {
+		return err
+	}
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return err
+		}
+		message := string(raw)
+		reply, err := script.Reply(message)
+		if err != nil {
+			return err
+		}
+		if reply == nil {
+			continue
+		}
+		if err := sendMessages(ctx, r, ws, reply.Messages, message); err != nil {
+			return err
+		}
+	}
+}
+
+func sendMessages(ctx context.Context, r *http.Request, ws *wsConn, messages []core.WebSocketMessage, incoming string) error {
+	for _, m := range messages {
+		if err := core.Sleep(ctx, m.Delay); err != nil {
+			return err
+		}
+		data, err := m.Render(r, incoming)
+		if err != nil {
+			return err
+		}
+		if err := ws.send(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runPush(ctx context.Context, r *http.Request, ws *wsConn, push core.WebSocketPush) {
+	wait := push.After
+	for {
+		if core.Sleep(ctx, wait) != nil {
+			return
+		}
+		data, err := push.Render(r)
+		if err != nil {
+			log.Printf("Error while rendering WebSocket push: %s", err)
+			return
+		}
+		if err := ws.send(data); err != nil {
+			return
+		}
+		if push.Interval <= 0 {
+			return
+		}
+		wait = push.Interval
+	}
+}
@@ -0,0 +1,7 @@
+package openapi
+
+import "errors"
+
+var (
+	ErrNoOperations = errors.New("the document has no operations to mock")
+)
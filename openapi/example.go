@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxExampleDepth stops the generation of recursive schemas
+const maxExampleDepth = 8
+
+// GenerateExample builds a value satisfying the schema, preferring its
+// example, default and first enum value to made up ones
+func GenerateExample(schema *openapi3.Schema) any {
+	return generate(schema, 0)
+}
+
+func generate(schema *openapi3.Schema, depth int) any {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		return generateAllOf(schema.AllOf, depth)
+	case len(schema.OneOf) > 0:
+		return generate(schema.OneOf[0].Value, depth+1)
+	case len(schema.AnyOf) > 0:
+		return generate(schema.AnyOf[0].Value, depth+1)
+	}
+
+	types := schema.Type
+	switch {
+	case types.Is(openapi3.TypeObject) || (types == nil && len(schema.Properties) > 0):
+		return generateObject(schema, depth)
+	case types.Is(openapi3.TypeArray):
+		if schema.Items == nil {
+			return []any{}
+		}
+		item := generate(schema.Items.Value, depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case types.Is(openapi3.TypeString):
+		return stringExample(schema)
+	case types.Is(openapi3.TypeInteger):
+		if schema.Min != nil {
+			return int64(*schema.Min)
+		}
+		return 0
+	case types.Is(openapi3.TypeNumber):
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		return 0.0
+	case types.Is(openapi3.TypeBoolean):
+		return true
+	default:
+		return nil
+	}
+}
+
+func generateObject(schema *openapi3.Schema, depth int) map[string]any {
+	obj := make(map[string]any, len(schema.Properties))
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := schema.Properties[name]; ref != nil {
+			if v := generate(ref.Value, depth+1); v != nil {
+				obj[name] = v
+			}
+		}
+	}
+	return obj
+}
+
+// generateAllOf merges the properties generated for every object schema
+func generateAllOf(refs openapi3.SchemaRefs, depth int) any {
+	merged := map[string]any{}
+	for _, ref := range refs {
+		v := generate(ref.Value, depth+1)
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for k, val := range obj {
+			merged[k] = val
+		}
+	}
+	return merged
+}
+
+func stringExample(schema *openapi3.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "byte":
+		return "c3RyaW5n"
+	default:
+		s := "string"
+		for uint64(len(s)) < schema.MinLength {
+			s += "-string"
+		}
+		return s
+	}
+}
@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDefinitions collects the definitions a loader serves for requests to
+// each of paths, dropping duplicates. Loaders serving the same definitions
+// whatever the request is, like the file and memory loaders, only need "/",
+// which is used when no path is given. Loaders that select definitions by
+// path prefix, like the database ones of the examples, need one path per prefix.
+func LoadDefinitions(ctx context.Context, loader core.Loader, paths ...string) ([]core.RouteDefinition, error) {
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+	defs := []core.RouteDefinition{}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		loaded, err := loader.Load(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range loaded {
+			key, err := json.Marshal(loaders.NewFileRouteDefinition(def))
+			if err != nil {
+				return nil, err
+			}
+			if !seen[string(key)] {
+				seen[string(key)] = true
+				defs = append(defs, def)
+			}
+		}
+	}
+	return defs, nil
+}
+
+// Export describes route definitions as an OpenAPI 3 document.
+//
+// Definitions sharing a method and path become one operation, with one response
+// per status code and one example per definition. HEADER, QUERY and COOKIE
+// matchers are documented as optional parameters. Templated bodies and headers
+// are rendered for a placeholder request whose path parameters are their own
+// names in braces, and whose query, headers and body are empty.
+// PROXY responses are documented without examples.
+func Export(info openapi3.Info, defs []core.RouteDefinition) (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &info,
+		Paths:   openapi3.NewPaths(),
+	}
+	for _, def := range defs {
+		path, params := openAPIPath(def.Path)
+		item := doc.Paths.Value(path)
+		if item == nil {
+			item = &openapi3.PathItem{}
+			doc.Paths.Set(path, item)
+		}
+		method := strings.ToUpper(def.Method)
+		op := item.GetOperation(method)
+		if op == nil {
+			op = openapi3.NewOperation()
+			op.Responses = openapi3.NewResponses()
+			op.Responses.Delete("default")
+			for _, name := range params {
+				op.AddParameter(openapi3.NewPathParameter(name).WithSchema(openapi3.NewStringSchema()))
+			}
+			item.SetOperation(method, op)
+		}
+		addMatcherParameters(op, def.Matchers)
+		responses := def.Responses
+		if len(responses) == 0 {
+			responses = []core.RouteResponse{def.Response}
+		}
+		for _, res := range responses {
+			if err := addResponse(op, def, res); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", def.Method, def.Path, err)
+			}
+		}
+	}
+	return doc, nil
+}
+
+// ExportLoader describes the definitions a loader serves for requests to paths,
+// see LoadDefinitions
+func ExportLoader(ctx context.Context, info openapi3.Info, loader core.Loader, paths ...string) (*openapi3.T, error) {
+	defs, err := LoadDefinitions(ctx, loader, paths...)
+	if err != nil {
+		return nil, err
+	}
+	return Export(info, defs)
+}
+
+// Encode encodes a document as YAML or JSON
+func Encode(doc *openapi3.T, format loaders.Format) ([]byte, error) {
+	switch format {
+	case loaders.FORMAT_YAML:
+		return yaml.Marshal(doc)
+	case loaders.FORMAT_JSON:
+		return json.MarshalIndent(doc, "", "  ")
+	default:
+		return nil, loaders.ErrUnsupportedFileFormat
+	}
+}
+
+// Handler serves the document describing what the loader serves for requests
+// to paths as JSON, exporting it again on each request so it is always current
+func Handler(info openapi3.Info, loader core.Loader, paths ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, err := ExportLoader(r.Context(), info, loader, paths...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		raw, err := Encode(doc, loaders.FORMAT_JSON)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(raw)
+	})
+}
+
+var chiParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// openAPIPath converts a chi pattern into an OpenAPI path, returning its
+// parameters. Regular expressions are dropped and a trailing wildcard
+// becomes a "wildcard" parameter.
+func openAPIPath(pattern string) (string, []string) {
+	params := []string{}
+	path := chiParam.ReplaceAllStringFunc(pattern, func(m string) string {
+		name := chiParam.FindStringSubmatch(m)[1]
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	if strings.HasSuffix(path, "*") {
+		path = strings.TrimSuffix(path, "*") + "{wildcard}"
+		params = append(params, "wildcard")
+	}
+	return path, params
+}
+
+func addMatcherParameters(op *openapi3.Operation, matchers []core.RequestMatcher) {
+	for _, m := range matchers {
+		var in string
+		switch m.Source {
+		case core.MATCHER_SOURCE_HEADER:
+			in = openapi3.ParameterInHeader
+		case core.MATCHER_SOURCE_QUERY:
+			in = openapi3.ParameterInQuery
+		case core.MATCHER_SOURCE_COOKIE:
+			in = openapi3.ParameterInCookie
+		default:
+			continue
+		}
+		if op.Parameters.GetByInAndName(in, m.Key) != nil {
+			continue
+		}
+		param := &openapi3.Parameter{In: in, Name: m.Key, Schema: openapi3.NewStringSchema().NewRef()}
+		if m.Operator == core.MATCH_OPERATOR_EQUALS {
+			param.Example = m.Value
+		}
+		op.AddParameter(param)
+	}
+}
+
+func addResponse(op *openapi3.Operation, def core.RouteDefinition, res core.RouteResponse) error {
+	statusCode := res.StatusCode
+	switch {
+	case res.Type == core.RESPONSE_TYPE_WEBSOCKET:
+		statusCode = http.StatusSwitchingProtocols
+	case statusCode == 0:
+		statusCode = http.StatusOK
+	}
+	key := strconv.Itoa(statusCode)
+	ref := op.Responses.Value(key)
+	if ref == nil {
+		description := http.StatusText(statusCode)
+		if description == "" {
+			description = "Response"
+		}
+		ref = &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(description)}
+		op.Responses.Set(key, ref)
+	}
+	if res.Type == core.RESPONSE_TYPE_PROXY || res.Type == core.RESPONSE_TYPE_WEBSOCKET {
+		return nil
+	}
+	result, err := res.BuildResponse(placeholderRequest(def))
+	if err != nil {
+		return err
+	}
+	response := ref.Value
+	contentType := "application/json"
+	if res.Type == core.RESPONSE_TYPE_SSE {
+		contentType = "text/event-stream"
+	}
+	names := make([]string, 0, len(result.Headers))
+	for name := range result.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = result.Headers[name]
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = openapi3.Headers{}
+		}
+		if _, ok := response.Headers[name]; !ok {
+			header := &openapi3.Header{Parameter: openapi3.Parameter{Schema: openapi3.NewStringSchema().NewRef(), Example: result.Headers[name]}}
+			response.Headers[name] = &openapi3.HeaderRef{Value: header}
+		}
+	}
+	if result.Body == nil || *result.Body == "" {
+		return nil
+	}
+	addExample(response, contentType, exampleValue(*result.Body, contentType))
+	return nil
+}
+
+// addExample sets the example of the media type, moving to named examples
+// when several definitions document the same response
+func addExample(response *openapi3.Response, contentType string, example any) {
+	if response.Content == nil {
+		response.Content = openapi3.Content{}
+	}
+	media := response.Content.Get(contentType)
+	if media == nil {
+		response.Content[contentType] = &openapi3.MediaType{Example: example}
+		return
+	}
+	if media.Examples == nil {
+		media.Examples = openapi3.Examples{"example-1": &openapi3.ExampleRef{Value: openapi3.NewExample(media.Example)}}
+		media.Example = nil
+	}
+	name := "example-" + strconv.Itoa(len(media.Examples)+1)
+	media.Examples[name] = &openapi3.ExampleRef{Value: openapi3.NewExample(example)}
+}
+
+func exampleValue(body, contentType string) any {
+	if !isJSON(contentType) {
+		return body
+	}
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	return value
+}
+
+// placeholderRequest is the request templates are rendered for, each path
+// parameter being its own name in braces
+func placeholderRequest(def core.RouteDefinition) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Method = def.Method
+	_, params := openAPIPath(def.Path)
+	rctx := chi.NewRouteContext()
+	for _, name := range params {
+		rctx.URLParams.Add(name, "{"+name+"}")
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,261 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PreferHeader selects which documented response is served, using the
+// code preference of the Prefer header, e.g. "Prefer: code=404"
+const PreferHeader = "Prefer"
+
+// Load reads and validates an OpenAPI 3 document, resolving its references
+func Load(ctx context.Context, path string) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ImportFile loads an OpenAPI 3 document and imports its operations
+func ImportFile(ctx context.Context, path string) ([]core.RouteDefinition, error) {
+	doc, err := Load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return Import(doc)
+}
+
+// Import creates route definitions for every operation of the document.
+//
+// Each operation answers with its lowest 2XX response, or its first documented
+// one when there is none. Other documented responses are served to requests
+// asking for them with the Prefer header, e.g. "Prefer: code=404".
+// Bodies are the examples of the response, or are generated from its schema
+// when it has no example.
+func Import(doc *openapi3.T) ([]core.RouteDefinition, error) {
+	defs := []core.RouteDefinition{}
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range sortedMethods(item) {
+			opDefs, err := importOperation(path, method, item.GetOperation(method))
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			defs = append(defs, opDefs...)
+		}
+	}
+	if len(defs) == 0 {
+		return nil, ErrNoOperations
+	}
+	if err := core.ValidateDefinitions(defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func sortedMethods(item *openapi3.PathItem) []string {
+	methods := []string{}
+	for method := range item.Operations() {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+type documentedResponse struct {
+	statusCode int
+	response   *openapi3.Response
+}
+
+func importOperation(path, method string, op *openapi3.Operation) ([]core.RouteDefinition, error) {
+	responses := documentedResponses(op)
+	if len(responses) == 0 {
+		return []core.RouteDefinition{
+			*core.NewRouteDefinition(path, method, *core.NewRouteResponse(core.RESPONSE_TYPE_STATIC, http.StatusOK, "", nil, 0)),
+		}, nil
+	}
+	primary := 0
+	for i, res := range responses {
+		if res.statusCode >= 200 && res.statusCode < 300 {
+			primary = i
+			break
+		}
+	}
+	defs := []core.RouteDefinition{}
+	for i, res := range responses {
+		response, err := routeResponse(res)
+		if err != nil {
+			return nil, err
+		}
+		def := core.NewRouteDefinition(path, method, *response)
+		if i != primary {
+			def.Matchers = []core.RequestMatcher{
+				*core.NewRequestMatcher(core.MATCHER_SOURCE_HEADER, PreferHeader, core.MATCH_OPERATOR_REGEX, `\bcode=`+strconv.Itoa(res.statusCode)+`\b`),
+			}
+		}
+		defs = append(defs, *def)
+	}
+	// the primary response is unconditional, so it must come after the others
+	primaryDef := defs[primary]
+	defs = append(defs[:primary], defs[primary+1:]...)
+	return append(defs, primaryDef), nil
+}
+
+// documentedResponses returns the responses of the operation ordered by status code.
+// Ranges such as 2XX stand for their first code. The default response stands
+// for 200 when it is the only one and for 500 otherwise, unless that code is taken.
+func documentedResponses(op *openapi3.Operation) []documentedResponse {
+	if op.Responses == nil {
+		return nil
+	}
+	byCode := make(map[int]*openapi3.Response)
+	var fallback *openapi3.Response
+	for key, ref := range op.Responses.Map() {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		code, isRange, ok := statusCode(key)
+		if !ok {
+			fallback = ref.Value
+			continue
+		}
+		// explicit codes take precedence over ranges
+		if _, taken := byCode[code]; !taken || !isRange {
+			byCode[code] = ref.Value
+		}
+	}
+	if fallback != nil {
+		code := http.StatusOK
+		if len(byCode) > 0 {
+			code = http.StatusInternalServerError
+		}
+		if _, taken := byCode[code]; !taken {
+			byCode[code] = fallback
+		}
+	}
+	responses := make([]documentedResponse, 0, len(byCode))
+	for code, res := range byCode {
+		responses = append(responses, documentedResponse{code, res})
+	}
+	sort.Slice(responses, func(i, j int) bool { return responses[i].statusCode < responses[j].statusCode })
+	return responses
+}
+
+var statusRange = regexp.MustCompile(`^[1-5]XX$`)
+
+// statusCode parses a response key, reporting whether it is a range such as 2XX
+func statusCode(key string) (code int, isRange bool, ok bool) {
+	if statusRange.MatchString(strings.ToUpper(key)) {
+		return int(key[0]-'0') * 100, true, true
+	}
+	code, err := strconv.Atoi(key)
+	return code, false, err == nil
+}
+
+func routeResponse(res documentedResponse) (*core.RouteResponse, error) {
+	headers := map[string]string{}
+	for name, ref := range res.response.Headers {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if example, ok := parameterExample(ref.Value.Parameter); ok {
+			headers[name] = fmt.Sprint(example)
+		}
+	}
+	contentType, media := pickMediaType(res.response.Content)
+	body := ""
+	if media != nil {
+		contentType = strings.Split(contentType, ";")[0]
+		headers["Content-Type"] = contentType
+		example := mediaExample(media)
+		if s, ok := example.(string); ok && !isJSON(contentType) {
+			body = s
+		} else if example != nil {
+			raw, err := json.Marshal(example)
+			if err != nil {
+				return nil, err
+			}
+			body = string(raw)
+		}
+	}
+	if len(headers) == 0 {
+		headers = nil
+	}
+	return core.NewRouteResponse(core.RESPONSE_TYPE_STATIC, res.statusCode, body, headers, 0), nil
+}
+
+// pickMediaType prefers JSON content, then the first media type in lexical order
+func pickMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if isJSON(t) {
+			return t, content[t]
+		}
+	}
+	return types[0], content[types[0]]
+}
+
+func isJSON(contentType string) bool {
+	contentType = strings.Split(contentType, ";")[0]
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// mediaExample returns the example of the media type, its first named example
+// or a value generated from its schema
+func mediaExample(media *openapi3.MediaType) any {
+	if media.Example != nil {
+		return media.Example
+	}
+	names := make([]string, 0, len(media.Examples))
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := media.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			return ref.Value.Value
+		}
+	}
+	if media.Schema != nil {
+		return GenerateExample(media.Schema.Value)
+	}
+	return nil
+}
+
+func parameterExample(p openapi3.Parameter) (any, bool) {
+	if p.Example != nil {
+		return p.Example, true
+	}
+	if p.Schema != nil && p.Schema.Value != nil {
+		if v := GenerateExample(p.Schema.Value); v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Validator checks requests against the operations of an OpenAPI 3 document.
+// Security requirements are not checked.
+type Validator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+}
+
+// NewValidator creates a validator for the operations of doc. Servers are
+// ignored, so operations are found by their path whatever the request host is.
+func NewValidator(doc *openapi3.T) (*Validator, error) {
+	local := *doc
+	local.Servers = nil
+	router, err := gorillamux.NewRouter(&local)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		router: router,
+		options: &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// Issue is a part of a request that does not conform to its operation.
+// In is where the issue was found: path, query, header, cookie or body;
+// Name is the parameter name or, for bodies, the path of the invalid field.
+type Issue struct {
+	In     string
+	Name   string
+	Reason string
+}
+
+// ValidationError lists every issue found in a request
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		reasons[i] = issue.In
+		if issue.Name != "" {
+			reasons[i] += " " + issue.Name
+		}
+		reasons[i] += ": " + issue.Reason
+	}
+	return "invalid request: " + strings.Join(reasons, "; ")
+}
+
+// Validate checks the path parameters, query, headers, cookies and body of the
+// request, returning a *ValidationError when they do not conform. Requests to
+// operations missing from the document are not validated.
+// The request body is restored so it can be read again.
+func (v *Validator) Validate(r *http.Request) error {
+	route, pathParams, err := v.router.FindRoute(r)
+	if err != nil {
+		return nil
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    v.options,
+	}
+	err = openapi3filter.ValidateRequest(r.Context(), input)
+	if err == nil {
+		return nil
+	}
+	return &ValidationError{Issues: issues(err)}
+}
+
+// issues flattens the errors of openapi3filter, which nests the schema errors of
+// a body in a MultiError wrapped by the RequestError of the body
+func issues(err error) []Issue {
+	var reqErr *openapi3filter.RequestError
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		found := []Issue{}
+		for _, inner := range e {
+			found = append(found, issues(inner)...)
+		}
+		return found
+	case *openapi3filter.RequestError:
+		reqErr = e
+	default:
+		return []Issue{{In: "request", Reason: err.Error()}}
+	}
+	var multi openapi3.MultiError
+	switch {
+	case reqErr.Parameter != nil:
+		return []Issue{{In: reqErr.Parameter.In, Name: reqErr.Parameter.Name, Reason: reason(reqErr)}}
+	case reqErr.RequestBody != nil:
+		if reqErr.Err != nil && errors.As(reqErr.Err, &multi) {
+			found := []Issue{}
+			for _, e := range multi {
+				found = append(found, bodyIssue(e))
+			}
+			return found
+		}
+		return []Issue{bodyIssue(reqErr)}
+	default:
+		return []Issue{{In: "request", Reason: reason(reqErr)}}
+	}
+}
+
+func bodyIssue(err error) Issue {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return Issue{In: "body", Name: strings.Join(schemaErr.JSONPointer(), "."), Reason: schemaErr.Reason}
+	}
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		return Issue{In: "body", Reason: reason(reqErr)}
+	}
+	return Issue{In: "body", Reason: err.Error()}
+}
+
+func reason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err.Err != nil && err.Reason == "" {
+		return err.Err.Error()
+	}
+	if err.Err != nil {
+		return err.Reason + ": " + err.Err.Error()
+	}
+	return err.Reason
+}
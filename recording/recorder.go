@@ -0,0 +1,113 @@
+package recording
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+)
+
+// ignoredHeaders are upstream response headers that are not worth replaying
+var ignoredHeaders = []string{
+	"Date",
+	"Request-Id",
+	"X-Forger-Req-Start",
+	"X-Forger-Req-End",
+}
+
+// Recorder captures the exchanges proxied to upstreams as STATIC route definitions.
+// Requests are told apart by method, path and query; only the first exchange of
+// each one is kept.
+type Recorder struct {
+	mu   sync.Mutex
+	defs []core.RouteDefinition
+	seen map[string]bool
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{seen: make(map[string]bool)}
+}
+
+// Record captures the upstream result returned for the request
+func (rec *Recorder) Record(r *http.Request, res core.Result) {
+	key := r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.seen[key] {
+		return
+	}
+	rec.seen[key] = true
+	rec.defs = append(rec.defs, newDefinition(r, res))
+}
+
+// Definitions returns the recorded definitions in the order they were captured
+func (rec *Recorder) Definitions() []core.RouteDefinition {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	defs := make([]core.RouteDefinition, len(rec.defs))
+	copy(defs, rec.defs)
+	return defs
+}
+
+// Save writes the recorded definitions to a YAML or JSON file, which can be served with loaders.ReadDefinitions
+func (rec *Recorder) Save(path string) error {
+	return loaders.WriteFile(path, rec.Definitions())
+}
+
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.defs = nil
+	rec.seen = make(map[string]bool)
+}
+
+func newDefinition(r *http.Request, res core.Result) core.RouteDefinition {
+	headers := make(map[string]string, len(res.Headers))
+	for name, value := range res.Headers {
+		headers[name] = value
+	}
+	for _, name := range ignoredHeaders {
+		for header := range headers {
+			if strings.EqualFold(header, name) {
+				delete(headers, header)
+			}
+		}
+	}
+	body := ""
+	if res.Body != nil {
+		body = *res.Body
+	}
+	response := core.NewRouteResponse(core.RESPONSE_TYPE_STATIC, res.StatusCode, body, headers, 0)
+	def := core.NewRouteDefinition(r.URL.Path, r.Method, *response)
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		def.Matchers = append(def.Matchers, *core.NewRequestMatcher(
+			core.MATCHER_SOURCE_QUERY, key, core.MATCH_OPERATOR_EQUALS, query.Get(key),
+		))
+	}
+	return *def
+}
+
+// ProxyDefinitions returns definitions forwarding every path of the most common methods to upstream
+func ProxyDefinitions(upstream string) []core.RouteDefinition {
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	defs := make([]core.RouteDefinition, len(methods))
+	for i, method := range methods {
+		response := core.RouteResponse{Type: core.RESPONSE_TYPE_PROXY, Upstream: upstream}
+		defs[i] = *core.NewRouteDefinition("/*", method, response)
+	}
+	return defs
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/admin"
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AdminAPI(t *testing.T) {
+	newServer := func() http.Handler {
+		store := loaders.NewMemoryLoader(core.RouteDefinition{
+			Path:   "/health",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"status": "ok"}`,
+			},
+		})
+		scenarios := core.NewInMemoryScenarioStore()
+		return mux.NewDynamicRouter(store,
+			mux.WithScenarioStore(scenarios),
+			mux.WithAdmin(admin.NewHandler(store, admin.WithScenarioStore(scenarios))),
+		)
+	}
+	do := func(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	const itemRoute = `{
+		"path": "/items/{id}",
+		"method": "GET",
+		"response": {"type": "DYNAMIC", "status_code": 200, "body": "{\"id\": \"{{ requestVar \"id\" }}\"}", "delay": "1ms"}
+	}`
+
+	t.Run("should create, update and delete route definitions", func(t *testing.T) {
+		h := newServer()
+		assert.Equal(t, http.StatusNotFound, do(h, "GET", "/items/1", "").Code)
+
+		rec := do(h, "POST", "/__admin/routes", itemRoute)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var created admin.Route
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, "1ms", created.Response.Delay)
+
+		rec = do(h, "GET", "/items/1", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id": "1"}`, rec.Body.String())
+
+		rec = do(h, "PUT", "/__admin/routes/"+created.ID, `{
+			"path": "/items/{id}",
+			"method": "GET",
+			"response": {"type": "STATIC", "status_code": 410, "body": {"gone": true}}
+		}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec = do(h, "GET", "/items/1", "")
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.JSONEq(t, `{"gone": true}`, rec.Body.String())
+
+		rec = do(h, "GET", "/__admin/routes", "")
+		var routes []admin.Route
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &routes))
+		assert.Len(t, routes, 2)
+
+		assert.Equal(t, http.StatusNoContent, do(h, "DELETE", "/__admin/routes/"+created.ID, "").Code)
+		assert.Equal(t, http.StatusNotFound, do(h, "GET", "/__admin/routes/"+created.ID, "").Code)
+		assert.Equal(t, http.StatusNotFound, do(h, "GET", "/items/1", "").Code)
+	})
+
+	t.Run("should reset to initial definitions", func(t *testing.T) {
+		h := newServer()
+		assert.Equal(t, http.StatusCreated, do(h, "POST", "/__admin/routes", itemRoute).Code)
+		assert.Equal(t, http.StatusNoContent, do(h, "POST", "/__admin/reset", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(h, "GET", "/items/1", "").Code)
+		assert.Equal(t, http.StatusOK, do(h, "GET", "/health", "").Code)
+	})
+
+	t.Run("should reject invalid definitions", func(t *testing.T) {
+		h := newServer()
+		rec := do(h, "POST", "/__admin/routes", `{"path": "/items", "method": "GET", "response": {"type": "DYNAMIC", "body": "{{ oops }}"}}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "bad_request")
+
+		rec = do(h, "POST", "/__admin/routes", `{"path": "/items"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DelayProfiles(t *testing.T) {
+	defs := []core.RouteDefinition{
+		{
+			Path:   "/slow",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:         core.RESPONSE_TYPE_STATIC,
+				StatusCode:   http.StatusOK,
+				DelayProfile: &core.DelayProfile{Distribution: core.DELAY_DISTRIBUTION_UNIFORM, Min: 20 * time.Millisecond, Max: 30 * time.Millisecond},
+			},
+		},
+		{
+			Path:     "/default",
+			Method:   "GET",
+			Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusOK},
+		},
+	}
+	r := mux.NewStaticRouter(defs, mux.WithDelayProfile(core.DelayProfile{Distribution: core.DELAY_DISTRIBUTION_FIXED, Fixed: 150 * time.Millisecond}))
+	elapsed := func(path string) time.Duration {
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return time.Since(start)
+	}
+
+	t.Run("should use the route delay profile", func(t *testing.T) {
+		d := elapsed("/slow")
+		assert.GreaterOrEqual(t, d, 20*time.Millisecond)
+		assert.Less(t, d, 150*time.Millisecond)
+	})
+
+	t.Run("should use the global delay profile", func(t *testing.T) {
+		assert.GreaterOrEqual(t, elapsed("/default"), 150*time.Millisecond)
+	})
+}
+
+func Test_InvalidGlobalDelayProfile(t *testing.T) {
+	profile := mux.WithDelayProfile(core.DelayProfile{Distribution: core.DELAY_DISTRIBUTION_UNIFORM, Min: 30 * time.Millisecond, Max: 20 * time.Millisecond})
+
+	t.Run("should refuse to build routers with an invalid delay profile", func(t *testing.T) {
+		assert.PanicsWithError(t, "delay profile: "+core.ErrInvalidDelayProfile.Error(), func() {
+			mux.NewStaticRouter(nil, profile)
+		})
+		assert.Panics(t, func() {
+			mux.NewDynamicRouter(nil, profile)
+		})
+	})
+}
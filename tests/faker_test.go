@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func luhnValid(number string) bool {
+	sum := 0
+	for i := range number {
+		d := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func ibanValid(iban string) bool {
+	var digits strings.Builder
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func Test_Faker(t *testing.T) {
+	fake := func(body, path string) map[string]string {
+		rec := serveTemplate(httptest.NewRequest(http.MethodGet, path, nil), body, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		values := map[string]string{}
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &values))
+		return values
+	}
+
+	t.Run("should generate valid credit card numbers and IBANs", func(t *testing.T) {
+		body := `{"card": "{{ fake "credit_card" }}", "iban": "{{ fake "iban" "de_DE" }}", "br": "{{ fake "iban" "pt_BR" }}", "us": "{{ fake "iban" }}"}`
+		for i := 0; i < 20; i++ {
+			values := fake(body, "/people/1")
+			assert.True(t, luhnValid(values["card"]), values["card"])
+			assert.True(t, strings.HasPrefix(values["iban"], "DE"))
+			assert.Len(t, values["iban"], 22)
+			assert.True(t, ibanValid(values["iban"]), values["iban"])
+			assert.Len(t, values["br"], 29)
+			assert.True(t, ibanValid(values["br"]), values["br"])
+			assert.True(t, ibanValid(values["us"]), values["us"])
+		}
+	})
+
+	t.Run("should generate the same values for the same seed", func(t *testing.T) {
+		body := `{"name": "{{ fake "name" "pt_BR" (requestVar "id") }}", "email": "{{ fake "email" "pt_BR" (requestVar "id") }}", "address": "{{ fake "address" "pt_BR" 7 }}"}`
+		first := fake(body, "/people/42")
+		assert.Equal(t, first, fake(body, "/people/42"))
+		assert.NotEqual(t, first["name"], "")
+		assert.Contains(t, first["email"], ".com.br")
+
+		// the seeded email belongs to the seeded name
+		names := strings.Fields(strings.ToLower(first["name"]))
+		local := strings.Split(first["email"], "@")[0]
+		assert.Equal(t, len(names), len(strings.Split(local, ".")))
+	})
+
+	t.Run("should generate lorem text and IPs", func(t *testing.T) {
+		body := `{"sentence": "{{ fake "sentence" }}", "ipv4": "{{ fake "ipv4" }}", "ipv6": "{{ fake "ipv6" }}", "phone": "{{ fake "phone" "fr_FR" }}", "company": "{{ fake "company" "es_ES" }}"}`
+		values := fake(body, "/people/1")
+		assert.True(t, strings.HasSuffix(values["sentence"], "."))
+		assert.Len(t, strings.Split(values["ipv4"], "."), 4)
+		assert.Len(t, strings.Split(values["ipv6"], ":"), 8)
+		assert.True(t, strings.HasPrefix(values["phone"], "+33 "))
+		assert.NotEmpty(t, values["company"])
+	})
+
+	t.Run("should fail on unknown types and locales", func(t *testing.T) {
+		for body, reason := range map[string]string{
+			`{{ fake "shoe_size" }}`:    "unknown fake type: shoe_size",
+			`{{ fake "name" "xx_XX" }}`: "unknown locale: xx_XX",
+		} {
+			rec := serveTemplate(httptest.NewRequest(http.MethodGet, "/people/1", nil), body, nil)
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Contains(t, rec.Body.String(), reason)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FallbackUpstream(t *testing.T) {
+	upstream := newUpstream()
+	defer upstream.Close()
+	defs := []core.RouteDefinition{
+		{
+			Path:   "/items/{id}",
+			Method: "GET",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_HEADER, Key: "X-Mock", Operator: core.MATCH_OPERATOR_EXISTS},
+			},
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"mocked": true}`,
+			},
+		},
+	}
+	routers := map[string]http.Handler{
+		"static":  mux.NewStaticRouter(defs, mux.WithFallbackUpstream(upstream.URL)),
+		"dynamic": mux.NewDynamicRouter(staticLoader(defs), mux.WithFallbackUpstream(upstream.URL)),
+	}
+
+	for name, r := range routers {
+		t.Run(name+" router should mock known routes", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+			req.Header.Set("X-Mock", "1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"mocked": true}`, rec.Body.String())
+			assert.Empty(t, rec.Header().Get("x-forger-proxied"))
+		})
+
+		t.Run(name+" router should forward unknown paths, methods and unmatched requests", func(t *testing.T) {
+			for _, req := range []*http.Request{
+				httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("order")),
+				httptest.NewRequest(http.MethodDelete, "/items/1", nil),
+				httptest.NewRequest(http.MethodGet, "/items/1", nil),
+			} {
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+				assert.Equal(t, http.StatusAccepted, rec.Code)
+				assert.Equal(t, "true", rec.Header().Get("x-forger-proxied"))
+				assert.Equal(t, "staging", rec.Header().Get("X-Upstream"))
+				assert.Contains(t, rec.Body.String(), req.URL.Path)
+			}
+		})
+	}
+
+	t.Run("should answer bad gateway when upstream is unreachable", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs, mux.WithFallbackUpstream("http://127.0.0.1:1"))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, rec.Body.String(), "bad_gateway")
+	})
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func faultServer(faults ...core.Fault) *httptest.Server {
+	return httptest.NewServer(mux.NewStaticRouter([]core.RouteDefinition{
+		{
+			Path:   "/items",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"id": 1, "name": "Item 1"}`,
+				Faults:     faults,
+			},
+		},
+	}))
+}
+
+func Test_Faults(t *testing.T) {
+	t.Run("should drop the connection", func(t *testing.T) {
+		for _, faultType := range []core.FaultType{
+			core.FAULT_TYPE_CONNECTION_RESET,
+			core.FAULT_TYPE_EMPTY_RESPONSE,
+			core.FAULT_TYPE_RANDOM_DATA,
+		} {
+			srv := faultServer(core.Fault{Type: faultType, Chance: 100})
+			_, err := http.Get(srv.URL + "/items")
+			assert.Error(t, err, faultType)
+			srv.Close()
+		}
+	})
+
+	t.Run("should answer bad gateway when the connection can not be hijacked", func(t *testing.T) {
+		srv := faultServer(core.Fault{Type: core.FAULT_TYPE_CONNECTION_RESET, Chance: 100})
+		defer srv.Close()
+		rec := httptest.NewRecorder()
+		srv.Config.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Equal(t, "CONNECTION_RESET", rec.Header().Get("x-forger-fault"))
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should truncate the body", func(t *testing.T) {
+		srv := faultServer(core.Fault{Type: core.FAULT_TYPE_MALFORMED_BODY, Chance: 100})
+		defer srv.Close()
+		res, err := http.Get(srv.URL + "/items")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, "MALFORMED_BODY", res.Header.Get("x-forger-fault"))
+		body, err := io.ReadAll(res.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, `{"id": 1, "na`, string(body))
+	})
+
+	t.Run("should drip the body slowly", func(t *testing.T) {
+		srv := faultServer(core.Fault{Type: core.FAULT_TYPE_SLOW_DRIP, Chance: 100, DripBytes: 10, DripInterval: 20 * time.Millisecond})
+		defer srv.Close()
+		start := time.Now()
+		res, err := http.Get(srv.URL + "/items")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"id": 1, "name": "Item 1"}`, string(body))
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("should inject faults according to their chance", func(t *testing.T) {
+		srv := faultServer(core.Fault{Type: core.FAULT_TYPE_EMPTY_RESPONSE, Chance: 0})
+		defer srv.Close()
+		res, err := http.Get(srv.URL + "/items")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res.Body.Close()
+
+		err = core.ValidateDefinitions([]core.RouteDefinition{
+			{Path: "/items", Method: "GET", Response: core.RouteResponse{
+				Type:   core.RESPONSE_TYPE_STATIC,
+				Faults: []core.Fault{{Type: core.FAULT_TYPE_EMPTY_RESPONSE, Chance: 60}, {Type: core.FAULT_TYPE_RANDOM_DATA, Chance: 60}},
+			}},
+		})
+		assert.ErrorIs(t, err, core.ErrInvalidFault)
+	})
+}
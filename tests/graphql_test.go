@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GraphQL(t *testing.T) {
+	r := mux.NewStaticRouter([]core.RouteDefinition{
+		{
+			Path:   "/graphql",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_GRAPHQL_OPERATION, Operator: core.MATCH_OPERATOR_EQUALS, Value: "GetUser"},
+				{Source: core.MATCHER_SOURCE_GRAPHQL_VARIABLE, Key: "id", Operator: core.MATCH_OPERATOR_EQUALS, Value: "404"},
+			},
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_GRAPHQL,
+				Body: `{"data": {"user": null}, "errors": [{"message": "user not found"}]}`,
+			},
+		},
+		{
+			Path:   "/graphql",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_GRAPHQL_OPERATION, Operator: core.MATCH_OPERATOR_EQUALS, Value: "GetUser"},
+			},
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_GRAPHQL,
+				Body: `{"user": {"id": {{ graphqlVariable "id" }}, "name": {{ graphqlVariable "name" "\"Jane\"" }}, "operation": "{{ graphqlOperation }}"}}`,
+			},
+		},
+		{
+			Path:   "/graphql",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_GRAPHQL_OPERATION, Operator: core.MATCH_OPERATOR_EQUALS, Value: "CreateUser"},
+			},
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_GRAPHQL,
+				Body: `{"createUser": {{ graphqlVariable "input" }}}`,
+			},
+		},
+		{
+			Path:   "/graphql",
+			Method: "GET",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_GRAPHQL_OPERATION, Operator: core.MATCH_OPERATOR_EQUALS, Value: "ListUsers"},
+			},
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_GRAPHQL,
+				Body: `{"users": [{"id": "1"}], "limit": {{ graphqlVariable "limit" }}}`,
+			},
+		},
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("should match operations named in the query document", func(t *testing.T) {
+		rec := post(`{"query": "query GetUser($id: ID!) { user(id: $id) { id name } }", "variables": {"id": "1"}}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"user": {"id": "1", "name": "Jane", "operation": "GetUser"}}}`, rec.Body.String())
+	})
+
+	t.Run("should match variables", func(t *testing.T) {
+		rec := post(`{"query": "query GetUser($id: ID!) { user(id: $id) { id } }", "variables": {"id": "404"}}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"user": null}, "errors": [{"message": "user not found"}]}`, rec.Body.String())
+	})
+
+	t.Run("should match the operation name of documents with several operations", func(t *testing.T) {
+		rec := post(`{
+			"query": "query GetUser { user { id } } mutation CreateUser($input: UserInput!) { createUser(input: $input) { id } }",
+			"operationName": "CreateUser",
+			"variables": {"input": {"name": "John"}}
+		}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"createUser": {"name": "John"}}}`, rec.Body.String())
+	})
+
+	t.Run("should read operations sent as query parameters", func(t *testing.T) {
+		q := url.Values{}
+		q.Set("query", "query ListUsers($limit: Int) { users(limit: $limit) { id } }")
+		q.Set("variables", `{"limit": 10}`)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"users": [{"id": "1"}], "limit": 10}}`, rec.Body.String())
+	})
+
+	t.Run("should not match unknown operations", func(t *testing.T) {
+		rec := post(`{"query": "query GetOrders { orders { id } }"}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmviniciuss/forger/grpcmock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const itemsProto = `
+syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+
+service Items {
+  rpc GetItem(GetItemRequest) returns (Item);
+  rpc WatchItems(WatchItemsRequest) returns (stream Item);
+}
+
+message GetItemRequest {
+  string item_id = 1;
+}
+
+message WatchItemsRequest {
+  int32 limit = 1;
+}
+
+message Item {
+  string item_id = 1;
+  string name = 2;
+  string tenant = 3;
+  google.protobuf.Timestamp created_at = 4;
+}
+`
+
+const itemsStubs = `
+stubs:
+  - service: shop.v1.Items
+    method: GetItem
+    matchers:
+      - key: item_id
+        operator: EQUALS
+        value: "404"
+    code: NOT_FOUND
+    message: item not found
+  - service: shop.v1.Items
+    method: GetItem
+    response: |
+      {
+        "item_id": {{ requestBody "item_id" }},
+        "name": "Item",
+        "tenant": "{{ requestHeader "x-tenant" }}",
+        "created_at": "2024-01-02T03:04:05Z"
+      }
+  - service: shop.v1.Items
+    method: WatchItems
+    stream:
+      - data: {"item_id": "1"}
+      - data: {"item_id": "2"}
+        delay: 10ms
+`
+
+func startGRPCMock(t *testing.T) (*grpc.ClientConn, *protoregistry.Files) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "items.proto"), []byte(itemsProto), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "stubs.yaml"), []byte(itemsStubs), 0o644))
+
+	files, err := grpcmock.LoadProtoFiles(context.Background(), []string{dir}, "items.proto")
+	assert.Nil(t, err)
+	stubs, err := grpcmock.ReadStubs(filepath.Join(dir, "stubs.yaml"))
+	assert.Nil(t, err)
+	srv, err := grpcmock.NewServer(files, stubs)
+	assert.Nil(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, files
+}
+
+func messageOf(t *testing.T, files *protoregistry.Files, name, data string) *dynamicpb.Message {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	assert.Nil(t, err)
+	msg := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	assert.Nil(t, protojson.Unmarshal([]byte(data), msg))
+	return msg
+}
+
+func Test_GRPCMock(t *testing.T) {
+	conn, files := startGRPCMock(t)
+
+	t.Run("should answer unary methods with templated messages", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+		out := messageOf(t, files, "shop.v1.Item", "{}")
+		err := conn.Invoke(ctx, "/shop.v1.Items/GetItem", messageOf(t, files, "shop.v1.GetItemRequest", `{"item_id": "42"}`), out)
+		assert.Nil(t, err)
+		raw, _ := protojson.Marshal(out)
+		assert.JSONEq(t, `{"itemId": "42", "name": "Item", "tenant": "acme", "createdAt": "2024-01-02T03:04:05Z"}`, string(raw))
+	})
+
+	t.Run("should answer with the error of matching stubs", func(t *testing.T) {
+		out := messageOf(t, files, "shop.v1.Item", "{}")
+		err := conn.Invoke(context.Background(), "/shop.v1.Items/GetItem", messageOf(t, files, "shop.v1.GetItemRequest", `{"item_id": "404"}`), out)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "item not found", status.Convert(err).Message())
+	})
+
+	t.Run("should stream messages of server streaming methods", func(t *testing.T) {
+		stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/shop.v1.Items/WatchItems")
+		assert.Nil(t, err)
+		assert.Nil(t, stream.SendMsg(messageOf(t, files, "shop.v1.WatchItemsRequest", `{"limit": 2}`)))
+		assert.Nil(t, stream.CloseSend())
+		var ids []string
+		for {
+			out := messageOf(t, files, "shop.v1.Item", "{}")
+			err := stream.RecvMsg(out)
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(t, err)
+			ids = append(ids, out.Get(out.Descriptor().Fields().ByName("item_id")).String())
+		}
+		assert.Equal(t, []string{"1", "2"}, ids)
+	})
+
+	t.Run("should refuse unknown methods", func(t *testing.T) {
+		out := messageOf(t, files, "shop.v1.Item", "{}")
+		err := conn.Invoke(context.Background(), "/shop.v1.Orders/GetOrder", messageOf(t, files, "shop.v1.GetItemRequest", "{}"), out)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("should reject stubs of unknown methods", func(t *testing.T) {
+		_, err := grpcmock.NewServer(files, []grpcmock.Stub{{Service: "shop.v1.Items", Method: "DeleteItem", Response: "{}"}})
+		assert.ErrorIs(t, err, grpcmock.ErrUnknownMethod)
+	})
+}
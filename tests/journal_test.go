@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/admin"
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/journal"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestJournal(t *testing.T) {
+	j := journal.New(10)
+	store := loaders.NewMemoryLoader(core.RouteDefinition{
+		Path:   "/orders/{id}",
+		Method: "POST",
+		Response: core.RouteResponse{
+			Type:       core.RESPONSE_TYPE_DYNAMIC,
+			StatusCode: http.StatusCreated,
+			Body:       `{"customer": {{ requestBody "customer" }}}`,
+		},
+	})
+	h := mux.NewDynamicRouter(store,
+		mux.WithJournal(j),
+		mux.WithAdmin(admin.NewHandler(store, admin.WithJournal(j))),
+	)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("request-id", "req-"+path)
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.JSONEq(t, `{"customer": 42}`, do("POST", "/orders/1", `{"customer": 42}`).Body.String())
+	do("POST", "/orders/2", `{"customer": 42}`)
+	do("POST", "/orders/3", `{"customer": 7}`)
+	do("GET", "/unknown", ``)
+
+	t.Run("should record served requests", func(t *testing.T) {
+		entries := j.Entries()
+		assert.Len(t, entries, 4)
+		assert.Equal(t, "req-/orders/1", entries[0].RequestID)
+		assert.Equal(t, "POST", entries[0].Method)
+		assert.Equal(t, "/orders/1", entries[0].Path)
+		assert.Equal(t, "/orders/{id}", entries[0].Route)
+		assert.Equal(t, `{"customer": 42}`, entries[0].Body)
+		assert.Equal(t, http.StatusCreated, entries[0].StatusCode)
+		assert.Equal(t, "", entries[3].Route)
+		assert.Equal(t, http.StatusNotFound, entries[3].StatusCode)
+	})
+
+	t.Run("should verify requests through the admin API", func(t *testing.T) {
+		verification := `{
+			"method": "POST",
+			"route": "/orders/{id}",
+			"matchers": [{"source": "BODY", "key": "customer", "operator": "EQUALS", "value": "42"}],
+			"count": %s
+		}`
+		rec := do("POST", "/__admin/requests/verify", strings.Replace(verification, "%s", "2", 1))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = do("POST", "/__admin/requests/verify", strings.Replace(verification, "%s", "3", 1))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "expected 3 request(s), got 2")
+
+		rec = do("POST", "/__admin/requests/count", `{"path": "/orders/3"}`)
+		assert.JSONEq(t, `{"count": 1}`, rec.Body.String())
+
+		rec = do("POST", "/__admin/requests/find", `{"method": "GET"}`)
+		var entries []journal.Entry
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "/unknown", entries[0].Path)
+	})
+
+	t.Run("should not record admin requests and clear the journal", func(t *testing.T) {
+		assert.Len(t, j.Entries(), 4)
+		assert.Equal(t, http.StatusNoContent, do("DELETE", "/__admin/requests", "").Code)
+		assert.Empty(t, j.Entries())
+	})
+}
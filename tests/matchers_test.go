@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestMatchers(t *testing.T) {
+	defs := []core.RouteDefinition{
+		{
+			Path:   "/payments",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_HEADER, Key: "X-Tenant", Operator: core.MATCH_OPERATOR_EQUALS, Value: "acme"},
+			},
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusAccepted,
+				Body:       `{"tenant": "acme"}`,
+			},
+		},
+		{
+			Path:   "/payments",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_BODY, Key: "amount", Operator: core.MATCH_OPERATOR_REGEX, Value: `^[0-9]{4,}$`},
+			},
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_DYNAMIC,
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       `{"amount": {{ requestBody "amount" }}}`,
+			},
+		},
+		{
+			Path:   "/payments",
+			Method: "POST",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_QUERY, Key: "dry_run", Operator: core.MATCH_OPERATOR_EXISTS},
+				{Source: core.MATCHER_SOURCE_COOKIE, Key: "session", Operator: core.MATCH_OPERATOR_CONTAINS, Value: "admin"},
+			},
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusNoContent,
+				Body:       ``,
+			},
+		},
+		{
+			Path:   "/payments",
+			Method: "POST",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusCreated,
+				Body:       `{"status": "created"}`,
+			},
+		},
+	}
+
+	t.Run("should select definition matching header", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs)
+		req := httptest.NewRequest(http.MethodPost, "/payments", bytes.NewReader([]byte(`{"amount": 10000}`)))
+		req.Header.Set("x-tenant", "acme")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.JSONEq(t, `{"tenant": "acme"}`, rec.Body.String())
+	})
+
+	t.Run("should keep request body available after matching on it", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs)
+		req := httptest.NewRequest(http.MethodPost, "/payments", bytes.NewReader([]byte(`{"amount": 10000}`)))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"amount": 10000}`, rec.Body.String())
+	})
+
+	t.Run("should require every matcher of a definition", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs)
+		req := httptest.NewRequest(http.MethodPost, "/payments?dry_run", bytes.NewReader([]byte(`{"amount": 1}`)))
+		req.AddCookie(&http.Cookie{Name: "session", Value: "user-admin-1"})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/payments?dry_run", bytes.NewReader([]byte(`{"amount": 1}`)))
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("should return not found when no definition matches", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs[:1])
+		req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should report invalid regex patterns when compiling", func(t *testing.T) {
+		def := core.RouteDefinition{
+			Path:     "/payments",
+			Method:   http.MethodPost,
+			Matchers: []core.RequestMatcher{{Source: core.MATCHER_SOURCE_HEADER, Key: "x-tenant", Operator: core.MATCH_OPERATOR_REGEX, Value: `^(acme`}},
+			Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC},
+		}
+		assert.ErrorContains(t, def.Compile(), "missing closing )")
+		assert.ErrorContains(t, def.Validate(), "missing closing )")
+	})
+
+	t.Run("should refuse to serve definitions with invalid matchers", func(t *testing.T) {
+		defs := []core.RouteDefinition{{
+			Path:     "/payments",
+			Method:   http.MethodPost,
+			Matchers: []core.RequestMatcher{{Source: "SESSION", Key: "id", Operator: core.MATCH_OPERATOR_EQUALS, Value: "1"}},
+			Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC},
+		}}
+		assert.PanicsWithError(t, "route [POST /payments]: "+core.ErrInvalidMatcherSource.Error(), func() {
+			mux.NewStaticRouter(defs)
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OpenAPIExport(t *testing.T) {
+	defs := []core.RouteDefinition{
+		{
+			Path:   "/items/{id}",
+			Method: "GET",
+			Matchers: []core.RequestMatcher{
+				{Source: core.MATCHER_SOURCE_HEADER, Key: "X-Tenant", Operator: core.MATCH_OPERATOR_EQUALS, Value: "acme"},
+			},
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_DYNAMIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"id": "{{ requestVar "id" }}", "tenant": "acme"}`,
+				Headers:    map[string]string{"X-Item": `{{ requestVar "id" }}`},
+			},
+		},
+		{
+			Path:   "/items/{id}",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"id": "1"}`,
+			},
+		},
+		{
+			Path:   "/items/{id:[0-9]+}",
+			Method: "DELETE",
+			Responses: []core.RouteResponse{
+				{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusNoContent},
+				{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusNotFound, Body: `{"error": "not found"}`},
+			},
+		},
+		{
+			Path:   "/health",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       "ok",
+				Headers:    map[string]string{"Content-Type": "text/plain"},
+			},
+		},
+	}
+	info := openapi3.Info{Title: "Items mock", Version: "1.0.0"}
+
+	t.Run("should describe paths, methods, status codes, headers and examples", func(t *testing.T) {
+		doc, err := openapi.Export(info, defs)
+		assert.Nil(t, err)
+		assert.Nil(t, doc.Validate(context.Background()))
+
+		get := doc.Paths.Value("/items/{id}").Get
+		assert.NotNil(t, get.Parameters.GetByInAndName("path", "id"))
+		tenant := get.Parameters.GetByInAndName("header", "X-Tenant")
+		assert.Equal(t, "acme", tenant.Example)
+		ok := get.Responses.Status(http.StatusOK).Value
+		assert.Equal(t, "{id}", ok.Headers["X-Item"].Value.Example)
+		examples := ok.Content.Get("application/json").Examples
+		assert.Equal(t, map[string]any{"id": "{id}", "tenant": "acme"}, examples["example-1"].Value.Value)
+		assert.Equal(t, map[string]any{"id": "1"}, examples["example-2"].Value.Value)
+
+		del := doc.Paths.Value("/items/{id}").Delete
+		assert.NotNil(t, del.Responses.Status(http.StatusNoContent))
+		assert.Equal(t, map[string]any{"error": "not found"}, del.Responses.Status(http.StatusNotFound).Value.Content.Get("application/json").Example)
+
+		health := doc.Paths.Value("/health").Get.Responses.Status(http.StatusOK).Value
+		assert.Equal(t, "ok", health.Content.Get("text/plain").Example)
+	})
+
+	t.Run("should export what a loader serves", func(t *testing.T) {
+		loader := loaders.NewMemoryLoader(defs...)
+		doc, err := openapi.ExportLoader(context.Background(), info, loader, "/items", "/health")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, doc.Paths.Len())
+
+		rec := httptest.NewRecorder()
+		openapi.Handler(info, loader).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var served map[string]any
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &served))
+		assert.Equal(t, "3.0.3", served["openapi"])
+	})
+
+	t.Run("should be importable again", func(t *testing.T) {
+		doc, err := openapi.Export(info, defs)
+		assert.Nil(t, err)
+		imported, err := openapi.Import(doc)
+		assert.Nil(t, err)
+		assert.Len(t, imported, 4)
+	})
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/bmviniciuss/forger/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const itemsSpec = `
+openapi: 3.0.3
+info:
+  title: Items
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      responses:
+        "200":
+          description: items
+          content:
+            application/json:
+              example: [{"id": "1", "name": "Item 1"}]
+        "503":
+          description: unavailable
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Error"
+    post:
+      responses:
+        "201":
+          description: created
+          headers:
+            Location:
+              schema:
+                type: string
+              example: /items/1
+          content:
+            application/json:
+              examples:
+                b:
+                  value: {"id": "b"}
+                a:
+                  value: {"id": "a"}
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "2XX":
+          description: item
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Item"
+        default:
+          description: error
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Error"
+  /health:
+    get:
+      responses:
+        "200":
+          description: health
+          content:
+            text/plain:
+              example: ok
+components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        code:
+          type: string
+          enum: [unavailable, not_found]
+        message:
+          type: string
+    Item:
+      allOf:
+        - type: object
+          properties:
+            id:
+              type: string
+              format: uuid
+            created_at:
+              type: string
+              format: date-time
+        - type: object
+          properties:
+            price:
+              type: number
+              minimum: 1.5
+            tags:
+              type: array
+              items:
+                type: string
+            active:
+              type: boolean
+            parent:
+              $ref: "#/components/schemas/Item"
+`
+
+func Test_OpenAPIImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(itemsSpec), 0o644))
+	defs, err := openapi.ImportFile(context.Background(), path)
+	assert.Nil(t, err)
+	r := mux.NewStaticRouter(defs)
+
+	serve := func(method, target string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should answer with the example of the success response", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/items", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{"id": "1", "name": "Item 1"}]`, rec.Body.String())
+	})
+
+	t.Run("should answer with the response preferred by the client", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/items", map[string]string{"Prefer": "code=503"})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"code": "unavailable", "message": "string"}`, rec.Body.String())
+	})
+
+	t.Run("should use the first named example and header examples", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/items", nil)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "/items/1", rec.Header().Get("Location"))
+		assert.JSONEq(t, `{"id": "a"}`, rec.Body.String())
+	})
+
+	t.Run("should generate bodies from schemas", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/items/42", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"id":"00000000-0000-0000-0000-000000000000"`)
+		assert.Contains(t, rec.Body.String(), `"created_at":"2024-01-01T00:00:00Z"`)
+		assert.Contains(t, rec.Body.String(), `"price":1.5`)
+		assert.Contains(t, rec.Body.String(), `"tags":["string"]`)
+		assert.Contains(t, rec.Body.String(), `"active":true`)
+
+		rec = serve(http.MethodGet, "/items/42", map[string]string{"Prefer": "code=500"})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"code": "unavailable", "message": "string"}`, rec.Body.String())
+	})
+
+	t.Run("should keep non JSON examples as they are", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/health", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "ok", rec.Body.String())
+	})
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/bmviniciuss/forger/recording"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUpstream() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Upstream", "staging")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"method": "` + r.Method + `", "path": "` + r.URL.Path + `", "page": "` + r.URL.Query().Get("page") + `", "body": "` + string(body) + `"}`))
+	}))
+}
+
+func Test_ProxyResponse(t *testing.T) {
+	t.Run("should forward requests to upstream", func(t *testing.T) {
+		upstream := newUpstream()
+		defer upstream.Close()
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{
+				Path:   "/items/{id}",
+				Method: "POST",
+				Response: core.RouteResponse{
+					Type:     core.RESPONSE_TYPE_PROXY,
+					Upstream: upstream.URL + "/api",
+					Headers:  map[string]string{"X-Item": `{{ requestVar "id" }}`},
+				},
+			},
+		})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items/1?page=2", strings.NewReader("data")))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.JSONEq(t, `{"method": "POST", "path": "/api/items/1", "page": "2", "body": "data"}`, rec.Body.String())
+		assert.Equal(t, "staging", rec.Header().Get("X-Upstream"))
+		assert.Equal(t, "1", rec.Header().Get("X-Item"))
+	})
+
+	t.Run("should answer bad gateway when the upstream can not be reached", func(t *testing.T) {
+		upstream := newUpstream()
+		upstream.Close()
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{Path: "/items", Method: "GET", Response: core.RouteResponse{Type: core.RESPONSE_TYPE_PROXY, Upstream: upstream.URL}},
+		})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Bad Gateway")
+	})
+
+	t.Run("should reject definitions without a valid upstream", func(t *testing.T) {
+		err := core.ValidateDefinitions([]core.RouteDefinition{
+			{Path: "/items", Method: "GET", Response: core.RouteResponse{Type: core.RESPONSE_TYPE_PROXY, Upstream: "staging"}},
+		})
+		assert.ErrorIs(t, err, core.ErrInvalidUpstream)
+	})
+}
+
+func Test_RecordAndPlayback(t *testing.T) {
+	upstream := newUpstream()
+	rec := recording.NewRecorder()
+	r := mux.NewStaticRouter(recording.ProxyDefinitions(upstream.URL), mux.WithRecorder(rec))
+	for _, path := range []string{"/items?page=1", "/items?page=2", "/items?page=1"} {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusAccepted, res.Code)
+	}
+	upstream.Close()
+
+	assert.Len(t, rec.Definitions(), 2)
+	file := filepath.Join(t.TempDir(), "recorded.yaml")
+	assert.Nil(t, rec.Save(file))
+
+	defs, err := loaders.ReadDefinitions(file)
+	assert.Nil(t, err)
+	replay := mux.NewStaticRouter(defs)
+	res := httptest.NewRecorder()
+	replay.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/items?page=2", nil))
+	assert.Equal(t, http.StatusAccepted, res.Code)
+	assert.JSONEq(t, `{"method": "GET", "path": "/items", "page": "2", "body": ""}`, res.Body.String())
+	assert.Equal(t, "staging", res.Header().Get("X-Upstream"))
+
+	res = httptest.NewRecorder()
+	replay.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/items?page=3", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
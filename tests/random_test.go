@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RandomFunctions(t *testing.T) {
+	t.Run("should generate values within bounds", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		for i := 0; i < 20; i++ {
+			n, err := strconv.Atoi(serveTemplate(req, `{{ randomInt 5 7 }}`, nil).Body.String())
+			assert.Nil(t, err)
+			assert.True(t, n >= 5 && n <= 7, n)
+
+			f := serveTemplate(req, `{{ randomFloat 1 2 3 }}`, nil).Body.String()
+			assert.Len(t, strings.Split(f, ".")[1], 3)
+
+			assert.Contains(t, []string{"PAID", "PENDING"}, serveTemplate(req, `{{ randomChoice "PAID" "PENDING" }}`, nil).Body.String())
+			assert.Contains(t, []string{"true", "false"}, serveTemplate(req, `{{ randomBool }}`, nil).Body.String())
+		}
+	})
+
+	t.Run("should generate integers across the whole int64 range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items?max=9223372036854775807", nil)
+		values := map[string]bool{}
+		for i := 0; i < 20; i++ {
+			values[serveTemplate(req, `{{ randomInt -9223372036854775808 (requestQuery "max") }}`, nil).Body.String()] = true
+			n, err := strconv.ParseInt(serveTemplate(req, `{{ randomInt 9223372036854775806 9223372036854775807 }}`, nil).Body.String(), 10, 64)
+			assert.Nil(t, err)
+			assert.True(t, n >= 9223372036854775806, n)
+		}
+		assert.Greater(t, len(values), 1)
+		assert.NotContains(t, values, "-9223372036854775808")
+	})
+
+	t.Run("should generate strings from charsets", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		assert.Len(t, serveTemplate(req, `{{ randomString }}`, nil).Body.String(), 16)
+		hex := serveTemplate(req, `{{ randomString 32 "hex" }}`, nil).Body.String()
+		assert.Len(t, hex, 32)
+		assert.Empty(t, strings.Trim(hex, "0123456789abcdef"))
+		assert.Empty(t, strings.Trim(serveTemplate(req, `{{ randomString 10 "xy" }}`, nil).Body.String(), "xy"))
+	})
+
+	t.Run("should be reproducible with a seed", func(t *testing.T) {
+		bodies := []string{}
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			bodies = append(bodies, serveTemplate(req, `{{ randomInt 0 1000000 }} {{ randomFloat 0 1 }} {{ randomString }}`, nil, mux.WithSeed(1)).Body.String())
+		}
+		assert.Equal(t, bodies[0], bodies[1])
+	})
+}
+
+func Test_ArithmeticFunctions(t *testing.T) {
+	t.Run("should compute values from the request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items?page=3&pageSize=20", strings.NewReader(`{"price": 10.5, "quantity": "4"}`))
+		body := serveTemplate(req, `{"total": {{ mul (requestQuery "page") (requestQuery "pageSize") }}, "amount": {{ mul (requestBody "price") (requestBody "quantity") }}}`, nil).Body.String()
+		assert.JSONEq(t, `{"total": 60, "amount": 42}`, body)
+	})
+
+	t.Run("should keep integers while every operand is one", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		assert.Equal(t, "6 -1 3.5 1 3", serveTemplate(req, `{{ add 1 2 3 }} {{ sub 1 2 }} {{ div 7 2 }} {{ mod 7 3 }} {{ round (div 5 2) }}`, nil).Body.String())
+		assert.Equal(t, "3.14 2.5", serveTemplate(req, `{{ round 3.14159 2 }} {{ add 1 1.5 }}`, nil).Body.String())
+		assert.Equal(t, "9007199254740993", serveTemplate(req, `{{ add "9007199254740992" 1 }}`, nil).Body.String())
+	})
+
+	t.Run("should fail on invalid operands", func(t *testing.T) {
+		for body, reason := range map[string]string{
+			`{{ div 1 0 }}`:       "division by zero",
+			`{{ randomInt 5 1 }}`: "max must not be lower than min",
+			`{{ add 1 "one" }}`:   "is not a number",
+		} {
+			rec := serveTemplate(httptest.NewRequest(http.MethodGet, "/items", nil), body, nil)
+			assert.Equal(t, http.StatusInternalServerError, rec.Code, body)
+			assert.Contains(t, rec.Body.String(), reason)
+		}
+	})
+}
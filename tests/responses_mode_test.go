@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func retryResponses() []core.RouteResponse {
+	return []core.RouteResponse{
+		{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusServiceUnavailable, Body: `{"status": "unavailable"}`},
+		{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusOK, Body: `{"status": "ok"}`},
+	}
+}
+
+func callStatuses(h http.Handler, path string, times int) []int {
+	statuses := make([]int, times)
+	for i := 0; i < times; i++ {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		statuses[i] = rec.Code
+	}
+	return statuses
+}
+
+func Test_ResponseModes(t *testing.T) {
+	t.Run("should stop on last response in sequential mode", func(t *testing.T) {
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{
+				Path:         "/health",
+				Method:       "GET",
+				Responses:    retryResponses(),
+				ResponseMode: core.RESPONSE_MODE_SEQUENTIAL,
+			},
+		})
+		assert.Equal(t, []int{503, 200, 200, 200}, callStatuses(r, "/health", 4))
+	})
+
+	t.Run("should start over in cyclic mode", func(t *testing.T) {
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{
+				Path:         "/health",
+				Method:       "GET",
+				Responses:    retryResponses(),
+				ResponseMode: core.RESPONSE_MODE_CYCLIC,
+			},
+		})
+		assert.Equal(t, []int{503, 200, 503, 200}, callStatuses(r, "/health", 4))
+	})
+
+	t.Run("should only pick listed responses in random mode", func(t *testing.T) {
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{
+				Path:         "/health",
+				Method:       "GET",
+				Responses:    retryResponses(),
+				ResponseMode: core.RESPONSE_MODE_RANDOM,
+			},
+		})
+		for _, status := range callStatuses(r, "/health", 20) {
+			assert.Contains(t, []int{503, 200}, status)
+		}
+	})
+
+	t.Run("should keep counters across requests of a dynamic router", func(t *testing.T) {
+		r := mux.NewDynamicRouter(staticLoader{
+			{
+				Path:      "/health",
+				Method:    "GET",
+				Responses: retryResponses(),
+			},
+		})
+		assert.Equal(t, []int{503, 200, 200}, callStatuses(r, "/health", 3))
+	})
+}
+
+type staticLoader []core.RouteDefinition
+
+func (l staticLoader) Load(r *http.Request) ([]core.RouteDefinition, error) {
+	return l, nil
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// unavailableStore fails like a store whose backend is down
+type unavailableStore struct {
+	core.ScenarioStore
+}
+
+func (unavailableStore) GetState(ctx context.Context, scenario string) (string, error) {
+	return "", errors.New("scenario store unavailable")
+}
+
+func Test_Scenarios(t *testing.T) {
+	defs := []core.RouteDefinition{
+		{
+			Path:          "/orders/{id}",
+			Method:        "GET",
+			Scenario:      "order-payment",
+			RequiredState: core.SCENARIO_STATE_STARTED,
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"status": "PENDING"}`,
+			},
+		},
+		{
+			Path:          "/orders/{id}",
+			Method:        "GET",
+			Scenario:      "order-payment",
+			RequiredState: "PAID",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       `{"status": "PAID"}`,
+			},
+		},
+		{
+			Path:     "/orders/{id}/pay",
+			Method:   "POST",
+			Scenario: "order-payment",
+			NewState: "PAID",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusNoContent,
+			},
+		},
+	}
+
+	getOrder := func(h http.Handler) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+		return rec.Body.String()
+	}
+
+	t.Run("should move scenario state after responding", func(t *testing.T) {
+		r := mux.NewStaticRouter(defs)
+		assert.JSONEq(t, `{"status": "PENDING"}`, getOrder(r))
+		assert.JSONEq(t, `{"status": "PENDING"}`, getOrder(r))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/1/pay", nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		assert.JSONEq(t, `{"status": "PAID"}`, getOrder(r))
+	})
+
+	t.Run("should use provided scenario store", func(t *testing.T) {
+		store := core.NewInMemoryScenarioStore()
+		store.SetState(context.Background(), "order-payment", "PAID")
+		r := mux.NewStaticRouter(defs, mux.WithScenarioStore(store))
+		assert.JSONEq(t, `{"status": "PAID"}`, getOrder(r))
+
+		store.Reset(context.Background())
+		assert.JSONEq(t, `{"status": "PENDING"}`, getOrder(r))
+	})
+
+	t.Run("should answer internal error when the scenario store fails", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.NewStaticRouter(defs, mux.WithScenarioStore(unavailableStore{})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "scenario store unavailable")
+	})
+}
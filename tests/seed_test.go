@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const seededBody = `{"id": "{{ uuid }}", "ulid": "{{ uuid "ulid" }}", "name": "{{ fake "name" }}", "card": "{{ fake "credit_card" }}"}`
+
+func Test_Seed(t *testing.T) {
+	get := func(path, seed string, opts ...mux.Option) *httptest.ResponseRecorder {
+		return serveTemplate(httptest.NewRequest(http.MethodGet, path, nil), seededBody, func(def *core.RouteDefinition) {
+			def.Seed = seed
+			def.Response.Headers = map[string]string{"Request-ID": "{{ uuid }}"}
+		}, opts...)
+	}
+
+	t.Run("should render the same values for every request with a global seed", func(t *testing.T) {
+		first := get("/orders/1", "", mux.WithSeed(42))
+		second := get("/orders/2", "", mux.WithSeed(42))
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, first.Header().Get("Request-ID"), second.Header().Get("Request-ID"))
+
+		// values within a response are still different from each other
+		assert.NotEqual(t, first.Header().Get("Request-ID"), "")
+		assert.NotContains(t, first.Body.String(), first.Header().Get("Request-ID"))
+
+		other := get("/orders/1", "", mux.WithSeed(43))
+		assert.NotEqual(t, first.Body.String(), other.Body.String())
+	})
+
+	t.Run("should derive the seed from the request", func(t *testing.T) {
+		seed := `{{ requestVar "id" }}`
+		first := get("/orders/1", seed)
+		assert.Equal(t, first.Body.String(), get("/orders/1", seed).Body.String())
+		assert.NotEqual(t, first.Body.String(), get("/orders/2", seed).Body.String())
+
+		// the global seed is combined with the route one
+		global := get("/orders/1", seed, mux.WithSeed(7))
+		assert.NotEqual(t, first.Body.String(), global.Body.String())
+		assert.Equal(t, global.Body.String(), get("/orders/1", seed, mux.WithSeed(7)).Body.String())
+	})
+
+	t.Run("should render several templated headers the same way", func(t *testing.T) {
+		headers := func() http.Header {
+			req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			return serveTemplate(req, "", func(def *core.RouteDefinition) {
+				def.Response.Headers = map[string]string{
+					"Request-ID":     "{{ uuid }}",
+					"Correlation-ID": "{{ uuid }}",
+					"Trace-ID":       "{{ uuid }}",
+					"Span-ID":        "{{ randomString 8 \"hex\" }}",
+				}
+			}, mux.WithSeed(42)).Header()
+		}
+		first := headers()
+		for i := 0; i < 20; i++ {
+			next := headers()
+			for _, name := range []string{"Request-ID", "Correlation-ID", "Trace-ID", "Span-ID"} {
+				assert.Equal(t, first.Get(name), next.Get(name), name)
+			}
+		}
+	})
+
+	t.Run("should answer internal error when the seed can not be rendered", func(t *testing.T) {
+		rec := get("/orders/1", `{{ div 1 0 }}`)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "division by zero")
+	})
+
+	t.Run("should keep values random without a seed", func(t *testing.T) {
+		assert.NotEqual(t, get("/orders/1", "").Body.String(), get("/orders/1", "").Body.String())
+	})
+
+	t.Run("should report invalid seed templates when validating definitions", func(t *testing.T) {
+		err := core.ValidateDefinitions([]core.RouteDefinition{
+			{
+				Path:     "/orders",
+				Method:   "GET",
+				Seed:     `{{ requestVar "id" `,
+				Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func sseServer(repeat int, events ...core.Event) *httptest.Server {
+	return httptest.NewServer(mux.NewStaticRouter([]core.RouteDefinition{
+		{
+			Path:   "/feeds/{id}",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:   core.RESPONSE_TYPE_SSE,
+				Events: events,
+				Repeat: repeat,
+			},
+		},
+	}))
+}
+
+func Test_SSE(t *testing.T) {
+	t.Run("should stream the events", func(t *testing.T) {
+		srv := sseServer(0,
+			core.Event{Name: "created", ID: "1", Data: `{"feed": "{{ requestVar "id" }}"}`},
+			core.Event{Data: "line 1\nline 2", Delay: 50 * time.Millisecond},
+		)
+		defer srv.Close()
+		start := time.Now()
+		res, err := http.Get(srv.URL + "/feeds/42")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "id: 1\nevent: created\ndata: {\"feed\": \"42\"}\n\ndata: line 1\ndata: line 2\n\n", string(body))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("should flush each event", func(t *testing.T) {
+		srv := sseServer(0,
+			core.Event{Data: "first"},
+			core.Event{Data: "second", Delay: time.Second},
+		)
+		defer srv.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/feeds/1", nil)
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		line, err := bufio.NewReader(res.Body).ReadString('\n')
+		assert.Nil(t, err)
+		assert.Equal(t, "data: first\n", line)
+	})
+
+	t.Run("should repeat the events", func(t *testing.T) {
+		srv := sseServer(2, core.Event{Data: "tick"})
+		defer srv.Close()
+		res, err := http.Get(srv.URL + "/feeds/1")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "data: tick\n\ndata: tick\n\ndata: tick\n\n", string(body))
+	})
+
+	t.Run("should stream forever until the client goes away", func(t *testing.T) {
+		srv := sseServer(core.REPEAT_FOREVER, core.Event{Data: "tick", Delay: 10 * time.Millisecond})
+		defer srv.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/feeds/1", nil)
+		res, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Greater(t, len(body), len("data: tick\n\n"))
+	})
+
+	t.Run("should reject invalid events", func(t *testing.T) {
+		for _, res := range []core.RouteResponse{
+			{Type: core.RESPONSE_TYPE_SSE},
+			{Type: core.RESPONSE_TYPE_SSE, Events: []core.Event{{Data: "tick", Delay: -time.Second}}},
+			{Type: core.RESPONSE_TYPE_SSE, Events: []core.Event{{Data: "tick"}}, Repeat: core.REPEAT_FOREVER},
+		} {
+			err := core.ValidateDefinitions([]core.RouteDefinition{{Path: "/feeds", Method: "GET", Response: res}})
+			assert.ErrorIs(t, err, core.ErrInvalidEvent)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/loaders"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveTemplate answers req with body as the DYNAMIC response of a route
+// matching /{resource} and /{resource}/{id} for the method of req. Route,
+// when not nil, customizes the route, like its seed or headers.
+func serveTemplate(req *http.Request, body string, route func(*core.RouteDefinition), opts ...mux.Option) *httptest.ResponseRecorder {
+	defs := []core.RouteDefinition{}
+	for _, path := range []string{"/{resource}", "/{resource}/{id}"} {
+		def := core.RouteDefinition{
+			Path:   path,
+			Method: req.Method,
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_DYNAMIC,
+				StatusCode: http.StatusOK,
+				Body:       body,
+			},
+		}
+		if route != nil {
+			route(&def)
+		}
+		defs = append(defs, def)
+	}
+	rec := httptest.NewRecorder()
+	mux.NewStaticRouter(defs, opts...).ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_CompiledTemplates(t *testing.T) {
+	t.Run("should bind compiled templates to each request", func(t *testing.T) {
+		r := mux.NewStaticRouter([]core.RouteDefinition{
+			{
+				Path:   "/items/{id}",
+				Method: "GET",
+				Response: core.RouteResponse{
+					Type:       core.RESPONSE_TYPE_DYNAMIC,
+					StatusCode: http.StatusOK,
+					Body:       `{"id": "{{ requestVar "id" }}"}`,
+					Headers:    map[string]string{"Item-ID": `{{ requestVar "id" }}`},
+				},
+			},
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%d", i), nil))
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.JSONEq(t, fmt.Sprintf(`{"id": "%d"}`, i), rec.Body.String())
+				assert.Equal(t, fmt.Sprint(i), rec.Header().Get("Item-ID"))
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("should report template errors when validating definitions", func(t *testing.T) {
+		err := core.ValidateDefinitions([]core.RouteDefinition{
+			{
+				Path:   "/items",
+				Method: "GET",
+				Response: core.RouteResponse{
+					Type: core.RESPONSE_TYPE_DYNAMIC,
+					Body: `{"id": "{{ unknownFunction }}"}`,
+				},
+			},
+		})
+		assert.ErrorContains(t, err, "unknownFunction")
+
+		err = core.ValidateDefinitions([]core.RouteDefinition{
+			{
+				Path:   "/items",
+				Method: "GET",
+				Response: core.RouteResponse{
+					Type:    core.RESPONSE_TYPE_STATIC,
+					Headers: map[string]string{"Item-ID": `{{ requestVar "id" `},
+				},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should not parse static bodies as templates", func(t *testing.T) {
+		def := core.RouteDefinition{
+			Path:   "/items",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_STATIC,
+				Body: `{{ not a template`,
+			},
+		}
+		assert.Nil(t, def.Compile())
+	})
+
+	t.Run("should fail to build routers with templates that do not compile", func(t *testing.T) {
+		defs := []core.RouteDefinition{
+			{
+				Path:   "/items/{id}",
+				Method: "GET",
+				Response: core.RouteResponse{
+					Type: core.RESPONSE_TYPE_DYNAMIC,
+					Body: `{"id": "{{ unknownFunction }}"}`,
+				},
+			},
+		}
+		assert.PanicsWithError(t, `route [GET /items/{id}]: template: :1: function "unknownFunction" not defined`, func() {
+			mux.NewStaticRouter(defs)
+		})
+
+		rec := httptest.NewRecorder()
+		mux.NewDynamicRouter(loaders.NewMemoryLoader(defs...)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unknownFunction")
+	})
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func throttleServer(throttle core.Throttle) *httptest.Server {
+	return httptest.NewServer(mux.NewStaticRouter([]core.RouteDefinition{
+		{
+			Path:   "/events",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type:       core.RESPONSE_TYPE_STATIC,
+				StatusCode: http.StatusOK,
+				Body:       "line 1\nline 2\nline 3\n",
+				Throttle:   &throttle,
+			},
+		},
+	}))
+}
+
+func Test_Throttle(t *testing.T) {
+	t.Run("should stream the body in chunks", func(t *testing.T) {
+		srv := throttleServer(core.Throttle{ChunkSize: 7, ChunkDelay: 50 * time.Millisecond})
+		defer srv.Close()
+		start := time.Now()
+		res, err := http.Get(srv.URL + "/events")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, []string{"chunked"}, res.TransferEncoding)
+
+		reader := bufio.NewReader(res.Body)
+		line, err := reader.ReadString('\n')
+		assert.Nil(t, err)
+		assert.Equal(t, "line 1\n", line)
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+		rest, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Equal(t, "line 2\nline 3\n", string(rest))
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("should limit the bandwidth", func(t *testing.T) {
+		srv := throttleServer(core.Throttle{BytesPerSecond: 100})
+		defer srv.Close()
+		start := time.Now()
+		res, err := http.Get(srv.URL + "/events")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "line 1\nline 2\nline 3\n", string(body))
+		// 21 bytes at 100 bytes per second, the last chunk being written without waiting
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("should delay the first byte after the headers", func(t *testing.T) {
+		srv := throttleServer(core.Throttle{FirstByteDelay: 100 * time.Millisecond})
+		defer srv.Close()
+		start := time.Now()
+		res, err := http.Get(srv.URL + "/events")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+		body, err := io.ReadAll(res.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "line 1\nline 2\nline 3\n", string(body))
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("should trip client read timeouts on slow bodies", func(t *testing.T) {
+		srv := throttleServer(core.Throttle{ChunkSize: 1, ChunkDelay: 50 * time.Millisecond})
+		defer srv.Close()
+		client := &http.Client{Timeout: 100 * time.Millisecond}
+		res, err := client.Get(srv.URL + "/events")
+		assert.Nil(t, err)
+		defer res.Body.Close()
+		_, err = io.ReadAll(res.Body)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject negative values", func(t *testing.T) {
+		err := core.ValidateDefinitions([]core.RouteDefinition{
+			{Path: "/events", Method: "GET", Response: core.RouteResponse{
+				Type:     core.RESPONSE_TYPE_STATIC,
+				Throttle: &core.Throttle{ChunkSize: -1},
+			}},
+		})
+		assert.ErrorIs(t, err, core.ErrInvalidThrottle)
+	})
+}
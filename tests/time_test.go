@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TimeFunction(t *testing.T) {
+	created := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"createdAt": "2024-02-28T22:30:15Z", "paidAt": 1709164800}`))
+	}
+
+	t.Run("should shift and format times parsed from the request", func(t *testing.T) {
+		cases := map[string]string{
+			`{{ time (parseTime (requestBody "createdAt")) }}`:                                                "2024-02-28T22:30:15.000Z",
+			`{{ time "+3d" (parseTime (requestBody "createdAt")) }}`:                                          "2024-03-02T22:30:15.000Z",
+			`{{ time "now-2h30m" (parseTime (requestBody "createdAt")) }}`:                                    "2024-02-28T20:00:15.000Z",
+			`{{ time "+1M-1d" (parseTime (requestBody "createdAt")) }}`:                                       "2024-03-27T22:30:15.000Z",
+			`{{ time "layout=2006-01-02" "+1d" (parseTime (requestBody "createdAt")) }}`:                      "2024-02-29",
+			`{{ time "%d/%m/%Y %H:%M" (parseTime (requestBody "createdAt")) }}`:                               "28/02/2024 22:30",
+			`{{ time "unix" (parseTime (requestBody "createdAt")) }}`:                                         "1709159415",
+			`{{ time "unixmilli" (parseTime (requestBody "paidAt")) }}`:                                       "1709164800000",
+			`{{ time "rfc3339" "tz=America/Sao_Paulo" (parseTime (requestBody "createdAt")) }}`:               "2024-02-28T19:30:15-03:00",
+			`{{ time "rfc3339" "truncate=day" (parseTime (requestBody "createdAt")) }}`:                       "2024-02-28T00:00:00Z",
+			`{{ time "rfc3339" "truncate=hour" "+45m" (parseTime (requestBody "createdAt")) }}`:               "2024-02-28T23:00:00Z",
+			`{{ time "%Y-%m-%d" (parseTime "28/02/2024" "%d/%m/%Y") }}`:                                       "2024-02-28",
+			`{{ time "%Y week 1, %d of Jan 2006 at %H:%M:%S.%L" (parseTime (requestBody "createdAt")) }}`:     "2024 week 1, 28 of Jan 2006 at 22:30:15.000",
+			`{{ time "%Y-%m-%d %% 100%" (parseTime "2024-02-28 15:04:05.250" "%Y-%m-%d %H:%M:%S.%L") }}`:      "2024-02-28 % 100%",
+			`{{ time "%H:%M:%S.%L" (parseTime "2024-02-28 15:04:05.250" "layout=2006-01-02 15:04:05.000") }}`: "15:04:05.250",
+			`{{ time "ISO8601" (parseTime (requestBody "createdAt")) }}`:                                      "2024-02-28T22:30:15.000Z",
+			`{{ time "iso" (parseTime (requestBody "createdAt")) }}`:                                          "2024-02-28T22:30:15.000Z",
+			`{{ time "rfc3339" "tz=Asia/Tokyo" "truncate=day" (parseTime (requestBody "createdAt")) }}`:       "2024-02-29T00:00:00+09:00",
+			`{{ time "+4000000s" "-5000000ms" (parseTime (requestBody "createdAt")) }}`:                       "2024-04-15T04:13:35.000Z",
+		}
+		for body, expected := range cases {
+			assert.Equal(t, expected, serveTemplate(created(), body, nil).Body.String(), body)
+		}
+	})
+
+	t.Run("should format the current time", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		before := time.Now()
+		unix, err := strconv.ParseInt(serveTemplate(req, `{{ time "unix" "+1h" }}`, nil).Body.String(), 10, 64)
+		assert.Nil(t, err)
+		assert.InDelta(t, before.Add(time.Hour).Unix(), unix, 2)
+
+		iso, err := time.Parse(time.RFC3339, serveTemplate(req, `{{ time "iso8601" "-5m" }}`, nil).Body.String())
+		assert.Nil(t, err)
+		assert.WithinDuration(t, before.Add(-5*time.Minute), iso, 2*time.Second)
+	})
+
+	t.Run("should fail on invalid options", func(t *testing.T) {
+		for body, reason := range map[string]string{
+			`{{ time "tz=Nowhere/City" }}`:                     "invalid timezone",
+			`{{ time "truncate=fortnight" }}`:                  "invalid truncation unit",
+			`{{ time (parseTime "yesterday") }}`:               "invalid time",
+			`{{ time (parseTime "28/02/2024" "02/01/2006") }}`: "invalid layout",
+			`{{ time (parseTime "2024 week 1" "%Y week 1") }}`: "can not be parsed",
+			`{{ time "+300000000h" }}`:                         "out of range",
+			`{{ time "+2000000h" "+2000000h" }}`:               "out of range",
+			`{{ time "+99999999999999999999y" }}`:              "out of range",
+			`{{ time "+20000y" }}`:                             "out of range",
+		} {
+			rec := serveTemplate(httptest.NewRequest(http.MethodGet, "/items", nil), body, nil)
+			assert.Equal(t, http.StatusInternalServerError, rec.Code, body)
+			assert.Contains(t, rec.Body.String(), reason)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/core/responses"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/bmviniciuss/forger/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const ordersSpec = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: 1.0.0
+servers:
+  - url: https://orders.example.com
+paths:
+  /orders/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: expand
+          in: query
+          schema:
+            type: boolean
+        - name: X-Tenant
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: order
+  /orders:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [item_id, quantity]
+              properties:
+                item_id:
+                  type: string
+                quantity:
+                  type: integer
+                  minimum: 1
+      responses:
+        "201":
+          description: created
+`
+
+func Test_RequestValidation(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(ordersSpec))
+	assert.Nil(t, err)
+	validator, err := openapi.NewValidator(doc)
+	assert.Nil(t, err)
+	r := mux.NewStaticRouter([]core.RouteDefinition{
+		{Path: "/orders/{id}", Method: "GET", Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusOK, Body: `{"id": 1}`}},
+		{Path: "/orders", Method: "POST", Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusCreated, Body: `{"id": 1}`}},
+		{Path: "/health", Method: "GET", Response: core.RouteResponse{Type: core.RESPONSE_TYPE_STATIC, StatusCode: http.StatusOK}},
+	}, mux.WithRequestValidation(validator))
+
+	serve := func(req *http.Request) (*httptest.ResponseRecorder, responses.Response) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		var res responses.Response
+		json.Unmarshal(rec.Body.Bytes(), &res)
+		return rec, res
+	}
+
+	t.Run("should serve conforming requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/orders/1?expand=true", nil)
+		req.Header.Set("X-Tenant", "acme")
+		rec, _ := serve(req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"item_id": "a", "quantity": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec, _ = serve(req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("should list invalid parameters", func(t *testing.T) {
+		rec, res := serve(httptest.NewRequest(http.MethodGet, "/orders/abc?expand=maybe", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_request", res.Error.Code)
+		var names []string
+		for _, d := range res.Error.Details {
+			names = append(names, d.In+" "+d.Name)
+		}
+		assert.ElementsMatch(t, []string{"path id", "query expand", "header X-Tenant"}, names)
+	})
+
+	t.Run("should point at invalid body fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"item_id": 1, "quantity": 0}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec, res := serve(req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var names []string
+		for _, d := range res.Error.Details {
+			assert.Equal(t, "body", d.In)
+			assert.NotEmpty(t, d.Reason)
+			names = append(names, d.Name)
+		}
+		assert.ElementsMatch(t, []string{"item_id", "quantity"}, names)
+	})
+
+	t.Run("should not validate undocumented operations", func(t *testing.T) {
+		rec, _ := serve(httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmviniciuss/forger/core"
+	"github.com/bmviniciuss/forger/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WebSocket(t *testing.T) {
+	srv := httptest.NewServer(mux.NewStaticRouter([]core.RouteDefinition{
+		{
+			Path:   "/markets/{symbol}",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_WEBSOCKET,
+				WebSocket: &core.WebSocketScript{
+					OnConnect: []core.WebSocketMessage{
+						{Data: `{"type": "welcome", "symbol": "{{ requestVar "symbol" }}"}`},
+					},
+					Replies: []core.WebSocketReply{
+						{
+							Matchers: []core.MessageMatcher{{Key: "type", Operator: core.MATCH_OPERATOR_EQUALS, Value: "subscribe"}},
+							Messages: []core.WebSocketMessage{
+								{Data: `{"type": "subscribed", "channel": {{ requestBody "channel" }}}`},
+							},
+						},
+						{
+							Matchers: []core.MessageMatcher{{Operator: core.MATCH_OPERATOR_EQUALS, Value: "ping"}},
+							Messages: []core.WebSocketMessage{{Data: "pong", Delay: 10 * time.Millisecond}},
+						},
+					},
+				},
+			},
+		},
+		{
+			Path:   "/ticker",
+			Method: "GET",
+			Response: core.RouteResponse{
+				Type: core.RESPONSE_TYPE_WEBSOCKET,
+				WebSocket: &core.WebSocketScript{
+					Pushes: []core.WebSocketPush{{Data: "tick", After: 10 * time.Millisecond, Interval: 10 * time.Millisecond}},
+				},
+			},
+		},
+	}))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	t.Run("should run the scripted conversation", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+"/markets/BTC", nil)
+		assert.Nil(t, err)
+		defer conn.Close()
+
+		_, msg, err := conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"type": "welcome", "symbol": "BTC"}`, string(msg))
+
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("unknown")))
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "subscribe", "channel": "trades"}`)))
+		_, msg, err = conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"type": "subscribed", "channel": "trades"}`, string(msg))
+
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("ping")))
+		_, msg, err = conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, "pong", string(msg))
+	})
+
+	t.Run("should push messages on a timer", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+"/ticker", nil)
+		assert.Nil(t, err)
+		defer conn.Close()
+		for i := 0; i < 3; i++ {
+			_, msg, err := conn.ReadMessage()
+			assert.Nil(t, err)
+			assert.Equal(t, "tick", string(msg))
+		}
+	})
+
+	t.Run("should refuse requests that are not upgrades", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/ticker")
+		assert.Nil(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should reject invalid scripts", func(t *testing.T) {
+		for _, res := range []core.RouteResponse{
+			{Type: core.RESPONSE_TYPE_WEBSOCKET},
+			{Type: core.RESPONSE_TYPE_WEBSOCKET, WebSocket: &core.WebSocketScript{
+				Pushes: []core.WebSocketPush{{Data: "tick", Interval: -time.Second}},
+			}},
+		} {
+			err := core.ValidateDefinitions([]core.RouteDefinition{{Path: "/ws", Method: "GET", Response: res}})
+			assert.ErrorIs(t, err, core.ErrInvalidWebSocketScript)
+		}
+	})
+
+	t.Run("should compile scripts once without changing the original", func(t *testing.T) {
+		script := core.WebSocketScript{
+			Replies: []core.WebSocketReply{{
+				Matchers: []core.MessageMatcher{{Key: "type", Operator: core.MATCH_OPERATOR_REGEX, Value: "^sub"}},
+				Messages: []core.WebSocketMessage{{Data: `{{ requestBody "type" }} {{ requestHeader "X-Client" }}`}},
+			}},
+		}
+		compiled, err := script.Compile()
+		assert.Nil(t, err)
+		reply, err := compiled.Reply(`{"type": "subscribe"}`)
+		assert.Nil(t, err)
+		assert.NotNil(t, reply)
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("X-Client", "web")
+		data, err := reply.Messages[0].Render(req, `{"type": "subscribe"}`)
+		assert.Nil(t, err)
+		assert.Equal(t, `"subscribe" web`, data)
+		assert.Equal(t, script.Replies[0].Messages[0].Data, compiled.Replies[0].Messages[0].Data)
+
+		script.Pushes = []core.WebSocketPush{{Data: `{{ unknownFunction }}`}}
+		_, err = script.Compile()
+		assert.ErrorContains(t, err, `function "unknownFunction" not defined`)
+	})
+}